@@ -1,4 +1,312 @@
 package main
 
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"pkg/ast"
+	"pkg/backend"
+	"pkg/lexer"
+	"pkg/parser"
+)
+
+const (
+	prompt             = "tugle> "
+	continuationPrompt = "    -> "
+)
+
+const helpMessage = `\dt          list tables
+\d <table>   describe table columns
+\?           show this help
+\q           quit`
+
+type TRepl struct {
+	backend     *backend.TMemoryBackend
+	out         io.Writer
+	interactive bool
+}
+
 func main() {
+	repl := &TRepl{
+		backend:     backend.NewMemoryBackend(),
+		out:         os.Stdout,
+		interactive: isTerminal(os.Stdin),
+	}
+
+	repl.Run(os.Stdin)
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (repl *TRepl) Run(in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	buffer := strings.Builder{}
+
+	for {
+		if repl.interactive {
+			if buffer.Len() == 0 {
+				fmt.Fprint(repl.out, prompt)
+			} else {
+				fmt.Fprint(repl.out, continuationPrompt)
+			}
+		}
+
+		if !scanner.Scan() {
+			break
+		}
+
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if buffer.Len() == 0 {
+			if line == "" {
+				continue
+			}
+
+			if strings.HasPrefix(line, "\\") {
+				if !repl.runMetaCommand(line) {
+					return
+				}
+				continue
+			}
+		}
+
+		buffer.WriteString(raw)
+		buffer.WriteString("\n")
+
+		if statementComplete(buffer.String(), line) {
+			repl.runQuery(buffer.String())
+			buffer.Reset()
+		}
+	}
+
+	if strings.TrimSpace(buffer.String()) != "" {
+		repl.runQuery(buffer.String())
+	}
+}
+
+func statementComplete(source string, line string) bool {
+	tokens, err := lexer.Tokenize(source)
+	if err != nil {
+		// An open string or block comment may still be closed by the next
+		// lines; any other lex error is reported once the statement ends.
+		var lexErr *lexer.LexError
+		if errors.As(err, &lexErr) && lexErr.Unterminated {
+			return false
+		}
+
+		return strings.HasSuffix(line, ";")
+	}
+
+	return len(tokens) > 0 && tokens[len(tokens)-1].Equal(lexer.SemicolonToken.AsToken())
+}
+
+func (repl *TRepl) runMetaCommand(line string) bool {
+	fields := strings.Fields(line)
+
+	switch fields[0] {
+	case "\\q":
+		return false
+	case "\\?":
+		fmt.Fprintln(repl.out, helpMessage)
+	case "\\dt":
+		results := &backend.TResults{
+			Columns: []*backend.TResultColumn{
+				{Name: "name", Type: backend.TextType},
+				{Name: "columns", Type: backend.IntType},
+				{Name: "rows", Type: backend.IntType},
+			},
+		}
+
+		for _, table := range repl.backend.Tables() {
+			results.Rows = append(results.Rows, []backend.TValue{
+				table.Name,
+				int64(len(table.Columns)),
+				int64(len(table.Rows)),
+			})
+		}
+
+		repl.printResults(results)
+	case "\\d":
+		name, ok := tableName(strings.TrimSpace(strings.TrimPrefix(line, fields[0])))
+		if !ok {
+			fmt.Fprintln(repl.out, "Usage: \\d <table>")
+			return true
+		}
+
+		table, err := repl.backend.Table(name)
+		if err != nil {
+			fmt.Fprintf(repl.out, "Error: %s\n", err)
+			return true
+		}
+
+		results := &backend.TResults{
+			Columns: []*backend.TResultColumn{
+				{Name: "column", Type: backend.TextType},
+				{Name: "type", Type: backend.TextType},
+				{Name: "nullable", Type: backend.TextType},
+			},
+		}
+
+		for _, column := range table.Columns {
+			nullable := "YES"
+			if column.NotNull {
+				nullable = "NO"
+			}
+
+			results.Rows = append(results.Rows, []backend.TValue{
+				column.Name,
+				column.TypeName(),
+				nullable,
+			})
+		}
+
+		repl.printResults(results)
+
+		for _, constraint := range table.Constraints {
+			fmt.Fprintf(repl.out, "  %s (%s)\n", constraint.Type, strings.Join(constraint.Columns, ", "))
+		}
+	default:
+		fmt.Fprintf(repl.out, "Unknown command %s, try \\?\n", fields[0])
+	}
+
+	return true
+}
+
+// tableName resolves a table name the way the parser does, folding unquoted
+// names to lower case and keeping quoted ones as written.
+func tableName(source string) (string, bool) {
+	tokens, err := lexer.Tokenize(source)
+	if err != nil || len(tokens) != 1 || tokens[0].Type != lexer.IdentifierType {
+		return "", false
+	}
+
+	return tokens[0].Value, true
+}
+
+func (repl *TRepl) runQuery(source string) {
+	tree, err := parser.Parse(source)
+	if err != nil {
+		fmt.Fprintf(repl.out, "Error: %s\n", err)
+
+		var parseErr *parser.ParseError
+		if errors.As(err, &parseErr) {
+			fmt.Fprintln(repl.out, parseErr.Excerpt)
+		}
+		return
+	}
+
+	for _, statement := range tree.Statements {
+		results, err := repl.backend.Execute(statement)
+		if err != nil {
+			fmt.Fprintf(repl.out, "Error: %s\n", err)
+			return
+		}
+
+		switch statement.Type {
+		case ast.InsertType, ast.UpdateType, ast.DeleteType:
+			repl.printRowCount(results.RowsAffected, "affected")
+			continue
+		}
+
+		if len(results.Columns) == 0 {
+			fmt.Fprintln(repl.out, "OK")
+			continue
+		}
+
+		repl.printResults(results)
+	}
+}
+
+func (repl *TRepl) printResults(results *backend.TResults) {
+	widths := make([]int, len(results.Columns))
+	for i, column := range results.Columns {
+		widths[i] = utf8.RuneCountInString(column.Name)
+	}
+
+	cells := make([][]string, len(results.Rows))
+	for i, row := range results.Rows {
+		cells[i] = make([]string, len(row))
+
+		for j, value := range row {
+			cells[i][j] = backend.FormatValue(value, results.Columns[j].Type)
+			if width := utf8.RuneCountInString(cells[i][j]); width > widths[j] {
+				widths[j] = width
+			}
+		}
+	}
+
+	separator := strings.Builder{}
+	separator.WriteString("+")
+	for _, width := range widths {
+		separator.WriteString(strings.Repeat("-", width+2))
+		separator.WriteString("+")
+	}
+
+	fmt.Fprintln(repl.out, separator.String())
+
+	header := make([]string, len(results.Columns))
+	for i, column := range results.Columns {
+		header[i] = column.Name
+	}
+	repl.printRow(header, widths, make([]bool, len(widths)))
+
+	fmt.Fprintln(repl.out, separator.String())
+
+	alignRight := make([]bool, len(results.Columns))
+	for i, column := range results.Columns {
+		alignRight[i] = column.Type.IsNumeric()
+	}
+
+	for _, row := range cells {
+		repl.printRow(row, widths, alignRight)
+	}
+
+	if len(cells) > 0 {
+		fmt.Fprintln(repl.out, separator.String())
+	}
+
+	repl.printRowCount(uint(len(cells)), "")
+}
+
+func (repl *TRepl) printRowCount(count uint, suffix string) {
+	noun := "rows"
+	if count == 1 {
+		noun = "row"
+	}
+
+	if suffix == "" {
+		fmt.Fprintf(repl.out, "(%d %s)\n", count, noun)
+	} else {
+		fmt.Fprintf(repl.out, "(%d %s %s)\n", count, noun, suffix)
+	}
+}
+
+func (repl *TRepl) printRow(row []string, widths []int, alignRight []bool) {
+	line := strings.Builder{}
+	line.WriteString("|")
+
+	for i, cell := range row {
+		padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+
+		if alignRight[i] {
+			line.WriteString(" " + padding + cell + " |")
+		} else {
+			line.WriteString(" " + cell + padding + " |")
+		}
+	}
+
+	fmt.Fprintln(repl.out, line.String())
 }
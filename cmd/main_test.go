@@ -0,0 +1,195 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"pkg/backend"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runRepl(input string) string {
+	out := strings.Builder{}
+	repl := &TRepl{backend: backend.NewMemoryBackend(), out: &out}
+
+	repl.Run(strings.NewReader(input))
+
+	return out.String()
+}
+
+func TestRepl_StatementComplete(t *testing.T) {
+	tests := []struct {
+		source   string
+		complete bool
+	}{
+		{source: "SELECT 1;", complete: true},
+		{source: "SELECT 1", complete: false},
+		{source: "SELECT 1; -- done", complete: true},
+		{source: "SELECT ';'", complete: false},
+		{source: "SELECT 'a;", complete: false},
+		{source: "SELECT 'a\n", complete: false},
+		{source: "SELECT 'a;\nb';", complete: true},
+		{source: "SELECT 1 /* a;", complete: false},
+		{source: "SELECT \"a;", complete: false},
+		{source: "SELECT !;", complete: true},
+		{source: "", complete: false},
+	}
+
+	for _, test := range tests {
+		lines := strings.Split(strings.TrimRight(test.source, "\n"), "\n")
+		line := strings.TrimSpace(lines[len(lines)-1])
+
+		assert.Equal(t, test.complete, statementComplete(test.source, line), test.source)
+	}
+}
+
+func TestRepl_PrintResults(t *testing.T) {
+	tests := []struct {
+		results *backend.TResults
+		output  string
+	}{
+		{
+			results: &backend.TResults{
+				Columns: []*backend.TResultColumn{
+					{Name: "id", Type: backend.IntType},
+					{Name: "name", Type: backend.TextType},
+				},
+				Rows: [][]backend.TValue{{int64(1), "ann"}, {int64(20), nil}},
+			},
+			output: "" +
+				"+----+------+\n" +
+				"| id | name |\n" +
+				"+----+------+\n" +
+				"|  1 | ann  |\n" +
+				"| 20 | NULL |\n" +
+				"+----+------+\n" +
+				"(2 rows)\n",
+		},
+		{
+			results: &backend.TResults{
+				Columns: []*backend.TResultColumn{{Name: "x", Type: backend.TextType}},
+				Rows:    [][]backend.TValue{{"héllo"}},
+			},
+			output: "" +
+				"+-------+\n" +
+				"| x     |\n" +
+				"+-------+\n" +
+				"| héllo |\n" +
+				"+-------+\n" +
+				"(1 row)\n",
+		},
+		{
+			results: &backend.TResults{
+				Columns: []*backend.TResultColumn{{Name: "empty", Type: backend.IntType}},
+			},
+			output: "" +
+				"+-------+\n" +
+				"| empty |\n" +
+				"+-------+\n" +
+				"(0 rows)\n",
+		},
+	}
+
+	for _, test := range tests {
+		out := strings.Builder{}
+		repl := &TRepl{out: &out}
+
+		repl.printResults(test.results)
+		assert.Equal(t, test.output, out.String())
+	}
+}
+
+func TestRepl_MetaCommands(t *testing.T) {
+	setup := `CREATE TABLE items (id INT PRIMARY KEY, name VARCHAR(10));
+CREATE TABLE "Mixed" (v TEXT);
+INSERT INTO items VALUES (1, 'a');
+`
+
+	tests := []struct {
+		input    string
+		contains []string
+		excludes []string
+	}{
+		{
+			input:    "\\dt\n",
+			contains: []string{"| items |       2 |    1 |", "| Mixed |       1 |    0 |"},
+		},
+		{
+			input:    "\\d items\n",
+			contains: []string{"| id     | INT         | NO       |", "| name   | VARCHAR(10) | YES      |", "  PRIMARY KEY (id)"},
+		},
+		{
+			input:    "\\d ITEMS\n",
+			contains: []string{"| id     | INT         | NO       |"},
+		},
+		{
+			input:    "\\d \"Mixed\"\n",
+			contains: []string{"| v      | TEXT | YES      |"},
+		},
+		{
+			input:    "\\d Mixed\n",
+			contains: []string{"Error: Table does not exist: mixed"},
+		},
+		{
+			input:    "\\d\n\\d a b\n",
+			contains: []string{"Usage: \\d <table>"},
+		},
+		{
+			input:    "\\?\n",
+			contains: []string{helpMessage},
+		},
+		{
+			input:    "\\x\n",
+			contains: []string{"Unknown command \\x, try \\?"},
+		},
+		{
+			input:    "\\q\nSELECT 1;\n",
+			excludes: []string{"(1 row)"},
+		},
+	}
+
+	for _, test := range tests {
+		output := runRepl(setup + test.input)
+
+		for _, expected := range test.contains {
+			assert.Contains(t, output, expected, test.input)
+		}
+
+		for _, unexpected := range test.excludes {
+			assert.NotContains(t, output, unexpected, test.input)
+		}
+	}
+}
+
+func TestRepl_Run(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{
+			input:  "SELECT 1\n  + 1 AS two;\n",
+			output: "+-----+\n| two |\n+-----+\n|   2 |\n+-----+\n(1 row)\n",
+		},
+		{
+			input:  "CREATE TABLE t (a INT);\nINSERT INTO t VALUES (1), (2);\n",
+			output: "OK\n(2 rows affected)\n",
+		},
+		{
+			input:  "    SELECT a FROM;\n",
+			output: "Error: Expected table name, got \";\", at 0:17\n    SELECT a FROM;\n                 ^\n",
+		},
+		{
+			input:  "SELECT 'a;\nb' AS s;\n",
+			output: "+------+\n| s    |\n+------+\n| a;\nb |\n+------+\n(1 row)\n",
+		},
+		{
+			input:  "SELECT 1\n",
+			output: "+----------+\n| ?column? |\n+----------+\n|        1 |\n+----------+\n(1 row)\n",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.output, runRepl(test.input), test.input)
+	}
+}
@@ -1,3 +1,16 @@
 module tugle
 
 go 1.21.1
+
+require (
+	github.com/stretchr/testify v1.8.4
+	pkg v0.0.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace pkg => ./pkg
@@ -1 +1,66 @@
 package ast
+
+func (expression *TExpression) Walk(visit func(*TExpression) bool) {
+	if expression == nil || !visit(expression) {
+		return
+	}
+
+	switch expression.Type {
+	case BinaryType:
+		expression.Binary.A.Walk(visit)
+		expression.Binary.B.Walk(visit)
+	case UnaryType:
+		expression.Unary.Operand.Walk(visit)
+	case IsType:
+		expression.Is.A.Walk(visit)
+		expression.Is.B.Walk(visit)
+	case InType:
+		expression.In.A.Walk(visit)
+		for _, value := range expression.In.List {
+			value.Walk(visit)
+		}
+	case BetweenType:
+		expression.Between.A.Walk(visit)
+		expression.Between.Low.Walk(visit)
+		expression.Between.High.Walk(visit)
+	case LikeType:
+		expression.Like.A.Walk(visit)
+		expression.Like.Pattern.Walk(visit)
+		expression.Like.Escape.Walk(visit)
+	case CaseType:
+		expression.Case.Operand.Walk(visit)
+		for _, when := range expression.Case.Whens {
+			when.Condition.Walk(visit)
+			when.Result.Walk(visit)
+		}
+		expression.Case.Else.Walk(visit)
+	case CastType:
+		expression.Cast.Operand.Walk(visit)
+	case FunctionType:
+		for _, argument := range expression.Function.Arguments {
+			argument.Walk(visit)
+		}
+	}
+}
+
+func (constraintType EConstraintType) String() string {
+	switch constraintType {
+	case PrimaryKeyConstraint:
+		return "PRIMARY KEY"
+	case UniqueConstraint:
+		return "UNIQUE"
+	}
+
+	return "CHECK"
+}
+
+func (operation ESetOperation) String() string {
+	switch operation {
+	case IntersectOperation:
+		return "INTERSECT"
+	case ExceptOperation:
+		return "EXCEPT"
+	}
+
+	return "UNION"
+}
@@ -4,46 +4,303 @@ import "pkg/lexer"
 
 type EStatementType uint
 type EExpressionType uint
+type EAlterTableAction uint
+type EConstraintType uint
+type ENullsOrder uint
+type ETableExpressionType uint
+type EJoinType uint
+type ESetOperation uint
 
 const (
 	LiteralType EExpressionType = iota
+	BinaryType
+	UnaryType
+	IsType
+	QualifiedType
+	FunctionType
+	SubqueryType
+	ExistsType
+	InType
+	BetweenType
+	LikeType
+	CaseType
+	CastType
 )
 
 const (
 	SelectType EStatementType = iota
 	CreateTableType
 	InsertType
+	UpdateType
+	DeleteType
+	DropTableType
+	AlterTableType
+)
+
+const (
+	AddColumnAction EAlterTableAction = iota
+	DropColumnAction
+	RenameColumnAction
+	RenameTableAction
+)
+
+const (
+	PrimaryKeyConstraint EConstraintType = iota
+	UniqueConstraint
+	CheckConstraint
+)
+
+const (
+	TableReferenceType ETableExpressionType = iota
+	JoinExpressionType
+	DerivedTableType
+)
+
+const (
+	InnerJoin EJoinType = iota
+	LeftJoin
+	RightJoin
+	FullJoin
+	CrossJoin
+)
+
+const (
+	UnionOperation ESetOperation = iota
+	IntersectOperation
+	ExceptOperation
+)
+
+const (
+	DefaultNulls ENullsOrder = iota
+	NullsFirst
+	NullsLast
 )
 
 type TColumnMeta struct {
-	Name     lexer.TToken
-	Datatype lexer.TToken
+	Name           lexer.TToken
+	Datatype       lexer.TToken
+	DatatypeParams []lexer.TToken
+	Default        *TExpression
+	PrimaryKey     bool
+	NotNull        bool
+	Unique         bool
+	Checks         []*TExpression
 }
 
+type TTableConstraint struct {
+	Type    EConstraintType
+	Columns []lexer.TToken
+	Check   *TExpression
+}
+
+type TBinaryExpression struct {
+	A        *TExpression
+	B        *TExpression
+	Operator lexer.TToken
+}
+
+type TUnaryExpression struct {
+	Operand  *TExpression
+	Operator lexer.TToken
+}
+
+type TIsExpression struct {
+	A        *TExpression
+	B        *TExpression
+	Not      bool
+	Distinct bool
+}
+
+type TQualifiedColumn struct {
+	Table  lexer.TToken
+	Column lexer.TToken
+}
+
+// TFunctionCall is name(arguments). Star marks COUNT(*), which has no
+// arguments, and Distinct marks name(DISTINCT argument).
+type TFunctionCall struct {
+	Name      lexer.TToken
+	Arguments []*TExpression
+	Star      bool
+	Distinct  bool
+}
+
+// TInExpression tests A against either a subquery or a list of values.
+type TInExpression struct {
+	A        *TExpression
+	Subquery *TSelectStatement
+	List     []*TExpression
+	Not      bool
+}
+
+type TBetweenExpression struct {
+	A    *TExpression
+	Low  *TExpression
+	High *TExpression
+	Not  bool
+}
+
+type TLikeExpression struct {
+	A           *TExpression
+	Pattern     *TExpression
+	Escape      *TExpression
+	Insensitive bool
+	Not         bool
+}
+
+type TWhenClause struct {
+	Condition *TExpression
+	Result    *TExpression
+}
+
+// TCaseExpression.Operand is set for the simple form, CASE x WHEN 1 THEN ...,
+// in which conditions are values compared with the operand.
+type TCaseExpression struct {
+	Operand *TExpression
+	Whens   []*TWhenClause
+	Else    *TExpression
+}
+
+// TCastExpression converts its operand to the datatype, written either as
+// CAST(x AS type) or x::type.
+type TCastExpression struct {
+	Operand        *TExpression
+	Datatype       lexer.TToken
+	DatatypeParams []lexer.TToken
+}
+
+// TExpression.Subquery holds the query of scalar subqueries and EXISTS.
 type TExpression struct {
-	Literal *lexer.TToken
-	Type    EExpressionType
+	Literal   *lexer.TToken
+	Binary    *TBinaryExpression
+	Unary     *TUnaryExpression
+	Is        *TIsExpression
+	Qualified *TQualifiedColumn
+	Function  *TFunctionCall
+	Subquery  *TSelectStatement
+	In        *TInExpression
+	Between   *TBetweenExpression
+	Like      *TLikeExpression
+	Case      *TCaseExpression
+	Cast      *TCastExpression
+	Type      EExpressionType
 }
 
 type TInsertStatement struct {
-	Table  lexer.TToken
-	Values *[]*TExpression
+	Table   lexer.TToken
+	Columns []lexer.TToken
+	Values  [][]*TExpression
+	Select  *TSelectStatement
 }
 
 type TCreateTableStatement struct {
-	TableName lexer.TToken
-	Columns   *[]*TColumnMeta
+	TableName   lexer.TToken
+	Columns     *[]*TColumnMeta
+	Constraints []*TTableConstraint
+	As          *TSelectStatement
+}
+
+// TSelectRule is a select list item: an expression with an optional alias,
+// or a Star selecting all columns, of Table only when it is set.
+type TSelectRule struct {
+	Expression *TExpression
+	Alias      *lexer.TToken
+	Star       bool
+	Table      *lexer.TToken
+}
+
+type TOrderTerm struct {
+	Expression *TExpression
+	Descending bool
+	Nulls      ENullsOrder
+}
+
+type TTableReference struct {
+	Table lexer.TToken
+	Alias *lexer.TToken
+}
+
+// TJoin joins Left and Right. Comma separated tables are cross joins; On and
+// Using are mutually exclusive and both empty for cross joins.
+type TJoin struct {
+	Left  *TTableExpression
+	Right *TTableExpression
+	Type  EJoinType
+	On    *TExpression
+	Using []lexer.TToken
+}
+
+type TDerivedTable struct {
+	Select *TSelectStatement
+	Alias  *lexer.TToken
+}
+
+type TTableExpression struct {
+	Table   *TTableReference
+	Join    *TJoin
+	Derived *TDerivedTable
+	Type    ETableExpressionType
 }
 
 type TSelectStatement struct {
-	From  lexer.TToken
-	Rules []*TExpression
+	From     *TTableExpression
+	Rules    []*TSelectRule
+	Where    *TExpression
+	GroupBy  []*TExpression
+	Having   *TExpression
+	OrderBy  []*TOrderTerm
+	Limit    *TExpression
+	Offset   *TExpression
+	Compound *TCompoundSelect
+}
+
+// TCompoundSelect combines the results of two queries. A TSelectStatement
+// holding one has no select list of its own, and its ORDER BY, LIMIT and
+// OFFSET apply to the combined result.
+type TCompoundSelect struct {
+	Operation ESetOperation
+	All       bool
+	Left      *TSelectStatement
+	Right     *TSelectStatement
+}
+
+type TAssignment struct {
+	Column lexer.TToken
+	Value  *TExpression
+}
+
+type TUpdateStatement struct {
+	Table       lexer.TToken
+	Assignments []*TAssignment
+	Where       *TExpression
+}
+
+type TDeleteStatement struct {
+	Table lexer.TToken
+	Where *TExpression
+}
+
+type TDropTableStatement struct {
+	TableName lexer.TToken
+	IfExists  bool
+}
+
+type TAlterTableStatement struct {
+	TableName lexer.TToken
+	Action    EAlterTableAction
+	Column    *TColumnMeta
+	Target    lexer.TToken
+	NewName   lexer.TToken
 }
 
 type TStatement struct {
 	CreateTable *TCreateTableStatement
 	Select      *TSelectStatement
 	Insert      *TInsertStatement
+	Update      *TUpdateStatement
+	Delete      *TDeleteStatement
+	DropTable   *TDropTableStatement
+	AlterTable  *TAlterTableStatement
 	Type        EStatementType
 }
 
@@ -0,0 +1,526 @@
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+type tAccumulator interface {
+	add(value TValue) error
+	result() (TValue, error)
+}
+
+type tAggregateFunction struct {
+	star           bool
+	callOnNull     bool
+	resultType     func(argument EColumnType) (EColumnType, bool)
+	newAccumulator func() tAccumulator
+}
+
+var aggregateFunctions = map[string]*tAggregateFunction{
+	"count": {
+		star:           true,
+		resultType:     func(EColumnType) (EColumnType, bool) { return BigIntType, true },
+		newAccumulator: func() tAccumulator { return &tCountAccumulator{} },
+	},
+	"sum": {
+		resultType:     sumType,
+		newAccumulator: func() tAccumulator { return &tSumAccumulator{} },
+	},
+	"avg": {
+		resultType:     avgType,
+		newAccumulator: func() tAccumulator { return &tAvgAccumulator{} },
+	},
+	"min": {
+		resultType:     extremeType,
+		newAccumulator: func() tAccumulator { return &tExtremeAccumulator{sign: -1} },
+	},
+	"max": {
+		resultType:     extremeType,
+		newAccumulator: func() tAccumulator { return &tExtremeAccumulator{sign: 1} },
+	},
+}
+
+type tCountAccumulator struct {
+	count int64
+}
+
+type tSumAccumulator struct {
+	sum TValue
+}
+
+type tAvgAccumulator struct {
+	sum   TValue
+	count int64
+}
+
+type tExtremeAccumulator struct {
+	sign  int
+	value TValue
+}
+
+type tDistinctAccumulator struct {
+	accumulator tAccumulator
+	seen        map[string]bool
+}
+
+type tGroup struct {
+	row          []TValue
+	accumulators map[*ast.TFunctionCall]tAccumulator
+}
+
+func sumType(argument EColumnType) (EColumnType, bool) {
+	switch {
+	case isIntegerType(argument):
+		return BigIntType, true
+	case argument == RealType || argument == DoubleType:
+		return DoubleType, true
+	case argument == NumericType || argument == UnknownType:
+		return NumericType, true
+	}
+
+	return 0, false
+}
+
+func avgType(argument EColumnType) (EColumnType, bool) {
+	if isIntegerType(argument) {
+		return NumericType, true
+	}
+
+	return sumType(argument)
+}
+
+func extremeType(argument EColumnType) (EColumnType, bool) {
+	return argument, true
+}
+
+func (accumulator *tCountAccumulator) add(TValue) error {
+	accumulator.count++
+	return nil
+}
+
+func (accumulator *tCountAccumulator) result() (TValue, error) {
+	return accumulator.count, nil
+}
+
+func (accumulator *tSumAccumulator) add(value TValue) error {
+	if accumulator.sum == nil {
+		accumulator.sum = value
+		return nil
+	}
+
+	sum, err := applyArithmetic(string(lexer.PlusToken), accumulator.sum, value)
+	if err != nil {
+		return err
+	}
+
+	accumulator.sum = sum
+	return nil
+}
+
+func (accumulator *tSumAccumulator) result() (TValue, error) {
+	return accumulator.sum, nil
+}
+
+func (accumulator *tAvgAccumulator) add(value TValue) error {
+	switch number := value.(type) {
+	case int64:
+		// Integers are averaged as NUMERIC, which cannot overflow.
+		value = TNumeric(strconv.FormatInt(number, 10))
+	case float64, TNumeric:
+	default:
+		return fmt.Errorf("%w: cannot average %s", ErrTypeMismatch, valueType(value))
+	}
+
+	if accumulator.sum == nil {
+		accumulator.sum = value
+	} else {
+		sum, err := applyArithmetic(string(lexer.PlusToken), accumulator.sum, value)
+		if err != nil {
+			return err
+		}
+
+		accumulator.sum = sum
+	}
+
+	accumulator.count++
+	return nil
+}
+
+func (accumulator *tAvgAccumulator) result() (TValue, error) {
+	if accumulator.count == 0 {
+		return nil, nil
+	}
+
+	return applyArithmetic(string(lexer.SlashToken), accumulator.sum, accumulator.count)
+}
+
+func (accumulator *tExtremeAccumulator) add(value TValue) error {
+	if accumulator.value == nil {
+		accumulator.value = value
+		return nil
+	}
+
+	cmp, err := compareValues(value, accumulator.value)
+	if err != nil {
+		return err
+	}
+
+	if cmp*accumulator.sign > 0 {
+		accumulator.value = value
+	}
+
+	return nil
+}
+
+func (accumulator *tExtremeAccumulator) result() (TValue, error) {
+	return accumulator.value, nil
+}
+
+func (accumulator *tDistinctAccumulator) add(value TValue) error {
+	key := rowKey([]TValue{value})
+	if accumulator.seen[key] {
+		return nil
+	}
+
+	accumulator.seen[key] = true
+	return accumulator.accumulator.add(value)
+}
+
+func (accumulator *tDistinctAccumulator) result() (TValue, error) {
+	return accumulator.accumulator.result()
+}
+
+func (query *tQuery) isAggregate(expression *ast.TExpression) bool {
+	if expression.Type != ast.FunctionType {
+		return false
+	}
+
+	_, ok := query.mb.aggregateFunction(expression.Function.Name.Value)
+	return ok
+}
+
+// aggregateCalls collects the outermost aggregate calls of the expressions.
+func (query *tQuery) aggregateCalls(expressions ...*ast.TExpression) []*ast.TFunctionCall {
+	calls := []*ast.TFunctionCall{}
+
+	for _, expression := range expressions {
+		expression.Walk(func(node *ast.TExpression) bool {
+			if query.isAggregate(node) {
+				calls = append(calls, node.Function)
+				return false
+			}
+
+			return true
+		})
+	}
+
+	return calls
+}
+
+func (query *tQuery) rejectAggregates(expression *ast.TExpression, clause string) error {
+	if len(query.aggregateCalls(expression)) > 0 {
+		return fmt.Errorf("%w: aggregate functions are not allowed in %s", ErrInvalidGrouping, clause)
+	}
+
+	return nil
+}
+
+func functionSignature(name string, arguments []EColumnType) string {
+	names := make([]string, len(arguments))
+	for i, argument := range arguments {
+		names[i] = argument.String()
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(names, ", "))
+}
+
+func aggregateType(call *ast.TFunctionCall, scope *tRowContext) (EColumnType, error) {
+	name := call.Name.Value
+	aggregate, _ := scope.query.mb.aggregateFunction(name)
+
+	if call.Star {
+		if !aggregate.star {
+			return 0, fmt.Errorf("%w: %s(*)", ErrFunctionDoesNotExist, name)
+		}
+
+		resultType, _ := aggregate.resultType(UnknownType)
+		return resultType, nil
+	}
+
+	arguments := make([]EColumnType, len(call.Arguments))
+
+	for i, argument := range call.Arguments {
+		if len(scope.query.aggregateCalls(argument)) > 0 {
+			return 0, fmt.Errorf("%w: aggregate function calls cannot be nested", ErrInvalidGrouping)
+		}
+
+		argumentType, err := expressionType(argument, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		arguments[i] = argumentType
+	}
+
+	if len(arguments) == 1 {
+		if resultType, ok := aggregate.resultType(arguments[0]); ok {
+			return resultType, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrFunctionDoesNotExist, functionSignature(name, arguments))
+}
+
+// checkGrouped verifies that every column the expression references outside
+// of an aggregate call is covered by a GROUP BY expression. Columns of
+// enclosing queries are constant within a group.
+func (query *tQuery) checkGrouped(
+	expression *ast.TExpression,
+	groupBy []*ast.TExpression,
+	columns []*tRelationColumn,
+) error {
+	var err error
+
+	expression.Walk(func(node *ast.TExpression) bool {
+		if err != nil || query.isAggregate(node) {
+			return false
+		}
+
+		for _, key := range groupBy {
+			if sameExpression(node, key, columns) {
+				return false
+			}
+		}
+
+		if table, name, ok := columnReference(node); ok {
+			if _, resolveErr := resolveColumn(columns, table, name); resolveErr != nil {
+				return false
+			}
+
+			err = fmt.Errorf(
+				"%w: column %s must appear in the GROUP BY clause or be used in an aggregate function",
+				ErrInvalidGrouping,
+				qualifiedName(table, name),
+			)
+			return false
+		}
+
+		return true
+	})
+
+	return err
+}
+
+func (query *tQuery) isGrouped(statement *ast.TSelectStatement, keys []*tSortKey) bool {
+	if len(statement.GroupBy) > 0 || statement.Having != nil {
+		return true
+	}
+
+	for _, rule := range statement.Rules {
+		if len(query.aggregateCalls(rule.Expression)) > 0 {
+			return true
+		}
+	}
+
+	for _, key := range keys {
+		if key.expression != nil && len(query.aggregateCalls(key.expression)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupByExpressions resolves GROUP BY terms given as select list positions
+// or output names that are not source columns to the select expressions.
+func groupByExpressions(
+	groupBy []*ast.TExpression,
+	rules []*ast.TSelectRule,
+	scope *tRowContext,
+) ([]*ast.TExpression, error) {
+	resolved := make([]*ast.TExpression, len(groupBy))
+
+	for i, term := range groupBy {
+		resolved[i] = term
+
+		if term.Type == ast.LiteralType && term.Literal.Type == lexer.NumericType {
+			position, err := strconv.ParseInt(term.Literal.Value, 10, 64)
+			if err == nil {
+				if position < 1 || position > int64(len(rules)) {
+					return nil, fmt.Errorf("%w: GROUP BY position %d is not in select list", ErrInvalidValue, position)
+				}
+
+				resolved[i] = rules[position-1].Expression
+			}
+		} else if term.Type == ast.LiteralType && term.Literal.Type == lexer.IdentifierType {
+			if _, err := resolveColumn(scope.columns, "", term.Literal.Value); err != nil {
+				for _, rule := range rules {
+					if rule.Alias != nil && rule.Alias.Value == term.Literal.Value {
+						resolved[i] = rule.Expression
+						break
+					}
+				}
+			}
+		}
+
+		if err := scope.query.rejectAggregates(resolved[i], "GROUP BY"); err != nil {
+			return nil, err
+		}
+
+		if _, err := expressionType(resolved[i], scope); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+// groupRelation evaluates aggregates per group and returns one row context
+// per group that passes HAVING. Without GROUP BY all rows form a single group.
+func groupRelation(
+	relation *tRelation,
+	statement *ast.TSelectStatement,
+	keys []*tSortKey,
+	query *tQuery,
+) ([]*tRowContext, error) {
+	scope := &tRowContext{columns: relation.columns, query: query}
+
+	groupBy, err := groupByExpressions(statement.GroupBy, statement.Rules, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := []*ast.TExpression{}
+	for _, rule := range statement.Rules {
+		grouped = append(grouped, rule.Expression)
+	}
+
+	for _, key := range keys {
+		if key.expression != nil {
+			grouped = append(grouped, key.expression)
+		}
+	}
+
+	if statement.Having != nil {
+		if err := checkCondition(statement.Having, scope); err != nil {
+			return nil, err
+		}
+
+		grouped = append(grouped, statement.Having)
+	}
+
+	for _, expression := range grouped {
+		if err := query.checkGrouped(expression, groupBy, relation.columns); err != nil {
+			return nil, err
+		}
+	}
+
+	calls := query.aggregateCalls(grouped...)
+	groups := []*tGroup{}
+	index := map[string]*tGroup{}
+
+	for _, row := range relation.rows {
+		ctx := &tRowContext{columns: relation.columns, row: row, query: query}
+
+		values := make([]TValue, len(groupBy))
+		for i, expression := range groupBy {
+			values[i], err = evaluateExpression(expression, ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		key := rowKey(values)
+
+		group, ok := index[key]
+		if !ok {
+			group = query.newGroup(row, calls)
+			index[key] = group
+			groups = append(groups, group)
+		}
+
+		if err := group.accumulate(calls, ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(groupBy) == 0 && len(groups) == 0 {
+		groups = append(groups, query.newGroup(make([]TValue, len(relation.columns)), calls))
+	}
+
+	contexts := []*tRowContext{}
+
+	for _, group := range groups {
+		ctx := &tRowContext{
+			columns:    relation.columns,
+			row:        group.row,
+			aggregates: map[*ast.TFunctionCall]TValue{},
+			query:      query,
+		}
+
+		for call, accumulator := range group.accumulators {
+			ctx.aggregates[call], err = accumulator.result()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		matches, err := evaluateCondition(statement.Having, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			contexts = append(contexts, ctx)
+		}
+	}
+
+	return contexts, nil
+}
+
+func (query *tQuery) newGroup(row []TValue, calls []*ast.TFunctionCall) *tGroup {
+	group := &tGroup{row: row, accumulators: map[*ast.TFunctionCall]tAccumulator{}}
+
+	for _, call := range calls {
+		aggregate, _ := query.mb.aggregateFunction(call.Name.Value)
+		accumulator := aggregate.newAccumulator()
+
+		if call.Distinct {
+			accumulator = &tDistinctAccumulator{accumulator: accumulator, seen: map[string]bool{}}
+		}
+
+		group.accumulators[call] = accumulator
+	}
+
+	return group
+}
+
+func (group *tGroup) accumulate(calls []*ast.TFunctionCall, ctx *tRowContext) error {
+	for _, call := range calls {
+		var value TValue = true
+
+		if !call.Star {
+			var err error
+
+			value, err = evaluateExpression(call.Arguments[0], ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		if aggregate, _ := ctx.query.mb.aggregateFunction(call.Name.Value); value == nil && !aggregate.callOnNull {
+			continue
+		}
+
+		if err := group.accumulators[call].add(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+
+	"pkg/ast"
+)
+
+// coercible reports whether values of one type convert to another. Implicit
+// coercions apply when a value is stored in a column by INSERT or UPDATE,
+// explicit ones additionally in CAST(x AS type) and x::type:
+//
+//	from \ to   numeric  TEXT  BOOLEAN   DATE/TIMESTAMP  BLOB
+//	numeric     yes      yes   explicit  -               -
+//	TEXT        yes      yes   yes       yes             yes
+//	BOOLEAN     explicit yes   yes       -               -
+//	DATE/TIME   -        yes   -         yes             -
+//	BLOB        -        yes   -         -               yes
+//
+// Numeric covers the integer, floating point and NUMERIC types; BOOLEAN
+// converts to and from integers only, as 1 and 0. Text is parsed when the
+// value is converted, so a coercion from TEXT can still fail at run time.
+// NULL converts to every type. Comparisons are checked by comparableTypes.
+func coercible(from EColumnType, to EColumnType, explicit bool) bool {
+	switch {
+	case from == UnknownType || to == UnknownType || from == to:
+		return true
+	case isTextType(from) || isTextType(to):
+		return true
+	case from.IsNumeric():
+		return to.IsNumeric() || (explicit && to == BoolType && isIntegerType(from))
+	case from == BoolType:
+		return explicit && isIntegerType(to)
+	case isTimeType(from):
+		return isTimeType(to)
+	}
+
+	return false
+}
+
+func castColumn(expression *ast.TCastExpression) (*TColumn, error) {
+	return datatypeOf(&ast.TColumnMeta{Datatype: expression.Datatype, DatatypeParams: expression.DatatypeParams})
+}
+
+func castType(expression *ast.TCastExpression, scope *tRowContext) (EColumnType, error) {
+	column, err := castColumn(expression)
+	if err != nil {
+		return 0, err
+	}
+
+	operandType, err := expressionType(expression.Operand, scope)
+	if err != nil {
+		return 0, err
+	}
+
+	if !coercible(operandType, column.Type, true) {
+		return 0, fmt.Errorf("%w: cannot cast %s to %s", ErrTypeMismatch, operandType, column.TypeName())
+	}
+
+	return column.Type, nil
+}
+
+func evaluateCast(expression *ast.TCastExpression, ctx *tRowContext) (TValue, error) {
+	column, err := castColumn(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := evaluateExpression(expression.Operand, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		if isIntegerType(column.Type) {
+			if v {
+				return int64(1), nil
+			}
+
+			return int64(0), nil
+		}
+	case int64:
+		if column.Type == BoolType {
+			return v != 0, nil
+		}
+	case time.Time:
+		// Dates and timestamps share a representation, so text is formatted
+		// by the declared type of the operand.
+		if isTextType(column.Type) {
+			operandType, err := expressionType(expression.Operand, ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			value = FormatValue(v, operandType)
+		}
+	}
+
+	// An explicit cast to VARCHAR(n) truncates instead of failing.
+	if column.Type == VarcharType && column.Length > 0 && value != nil {
+		if text := []rune(formatText(value)); uint(len(text)) > column.Length {
+			value = string(text[:column.Length])
+		}
+	}
+
+	return column.coerce(value)
+}
+
+// checkAssignment verifies that a value of the given type can be stored in
+// the column.
+func (table *TTable) checkAssignment(column *TColumn, valueType EColumnType) error {
+	if coercible(valueType, column.Type, false) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: column %s.%s is %s but expression is %s",
+		ErrTypeMismatch,
+		table.Name,
+		column.Name,
+		column.TypeName(),
+		valueType,
+	)
+}
@@ -0,0 +1,190 @@
+package backend
+
+import (
+	"fmt"
+
+	"pkg/ast"
+)
+
+// compoundStatement runs a set operation. Columns are named after the left
+// query and typed as for CASE. Rows are compared like GROUP BY keys, so NULLs
+// match each other, and without ALL the result has no duplicates.
+func (query *tQuery) compoundStatement(statement *ast.TSelectStatement) (*TResults, error) {
+	compound := statement.Compound
+
+	left, err := query.selectStatement(compound.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := query.selectStatement(compound.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(left.Columns) != len(right.Columns) {
+		return nil, fmt.Errorf(
+			"%w: each %s query must have the same number of columns",
+			ErrInvalidStatement,
+			compound.Operation,
+		)
+	}
+
+	results := &TResults{}
+
+	for i, column := range left.Columns {
+		columnType, ok := commonType(column.Type, right.Columns[i].Type)
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w: %s types %s and %s cannot be matched",
+				ErrTypeMismatch,
+				compound.Operation,
+				column.Type,
+				right.Columns[i].Type,
+			)
+		}
+
+		results.Columns = append(results.Columns, &TResultColumn{Name: column.Name, Type: columnType})
+	}
+
+	leftRows, err := coerceRows(left.Rows, results.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	rightRows, err := coerceRows(right.Rows, results.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	results.Rows = combineRows(compound, leftRows, rightRows)
+
+	return query.orderResults(statement, results)
+}
+
+func coerceRows(rows [][]TValue, columns []*TResultColumn) ([][]TValue, error) {
+	coerced := make([][]TValue, len(rows))
+
+	for i, row := range rows {
+		coerced[i] = make([]TValue, len(row))
+
+		for j, value := range row {
+			value, err := coerceDeclared(value, columns[j].Type)
+			if err != nil {
+				return nil, err
+			}
+
+			coerced[i][j] = value
+		}
+	}
+
+	return coerced, nil
+}
+
+// combineRows applies the set operation. With ALL a row that occurs m times
+// on the left and n times on the right occurs min(m, n) times in INTERSECT
+// and max(m-n, 0) times in EXCEPT.
+func combineRows(compound *ast.TCompoundSelect, left [][]TValue, right [][]TValue) [][]TValue {
+	if compound.Operation == ast.UnionOperation {
+		rows := append(append([][]TValue{}, left...), right...)
+		if compound.All {
+			return rows
+		}
+
+		return distinctRows(rows)
+	}
+
+	counts := map[string]int{}
+	for _, row := range right {
+		counts[rowKey(row)]++
+	}
+
+	rows := [][]TValue{}
+
+	for _, row := range left {
+		key := rowKey(row)
+		matched := counts[key] > 0
+
+		if matched && compound.All {
+			counts[key]--
+		}
+
+		if matched == (compound.Operation == ast.IntersectOperation) {
+			rows = append(rows, row)
+		}
+	}
+
+	if compound.All {
+		return rows
+	}
+
+	return distinctRows(rows)
+}
+
+func distinctRows(rows [][]TValue) [][]TValue {
+	distinct := [][]TValue{}
+	seen := map[string]bool{}
+
+	for _, row := range rows {
+		if key := rowKey(row); !seen[key] {
+			seen[key] = true
+			distinct = append(distinct, row)
+		}
+	}
+
+	return distinct
+}
+
+// orderResults applies ORDER BY, LIMIT and OFFSET of a compound statement,
+// which only see the columns of the combined result.
+func (query *tQuery) orderResults(statement *ast.TSelectStatement, results *TResults) (*TResults, error) {
+	relation := resultRelation(results)
+	scope := &tRowContext{columns: relation.columns, query: query}
+
+	for _, term := range statement.OrderBy {
+		if err := query.rejectAggregates(term.Expression, fmt.Sprintf("ORDER BY of %s", statement.Compound.Operation)); err != nil {
+			return nil, err
+		}
+	}
+
+	keys, err := sortKeys(statement.OrderBy, results.Columns, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset, err := pagingBounds(statement, query)
+	if err != nil {
+		return nil, err
+	}
+
+	sorter := newRowSorter(keys, limit)
+
+	for _, row := range relation.rows {
+		keyValues := make([]TValue, len(keys))
+
+		for i, key := range keys {
+			if key.column >= 0 {
+				keyValues[i] = row[key.column]
+				continue
+			}
+
+			value, err := evaluateExpression(key.expression, &tRowContext{columns: relation.columns, row: row, query: query})
+			if err != nil {
+				return nil, err
+			}
+
+			keyValues[i] = value
+		}
+
+		sorter.add(keyValues, row)
+	}
+
+	rows, err := sorter.sorted()
+	if err != nil {
+		return nil, err
+	}
+
+	results.Rows = rows[min(offset, len(rows)):]
+
+	return results, nil
+}
@@ -0,0 +1,351 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+func columnConstraints(columnMeta *ast.TColumnMeta) []*TConstraint {
+	constraints := []*TConstraint{}
+	columns := []string{columnMeta.Name.Value}
+
+	if columnMeta.PrimaryKey {
+		constraints = append(constraints, &TConstraint{Type: ast.PrimaryKeyConstraint, Columns: columns})
+	}
+
+	if columnMeta.Unique {
+		constraints = append(constraints, &TConstraint{Type: ast.UniqueConstraint, Columns: columns})
+	}
+
+	for _, check := range columnMeta.Checks {
+		constraints = append(constraints, &TConstraint{Type: ast.CheckConstraint, Check: check})
+	}
+
+	return constraints
+}
+
+func tableConstraint(constraintMeta *ast.TTableConstraint) *TConstraint {
+	constraint := &TConstraint{
+		Type:  constraintMeta.Type,
+		Check: constraintMeta.Check,
+	}
+
+	for _, column := range constraintMeta.Columns {
+		constraint.Columns = append(constraint.Columns, column.Value)
+	}
+
+	return constraint
+}
+
+func referencedColumns(expression *ast.TExpression) []string {
+	columns := []string{}
+	seen := map[string]bool{}
+
+	expression.Walk(func(expression *ast.TExpression) bool {
+		if expression.Type == ast.LiteralType && expression.Literal.Type == lexer.IdentifierType {
+			if name := expression.Literal.Value; !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		}
+
+		return true
+	})
+
+	return columns
+}
+
+// unqualifyColumns rewrites references qualified with the table's own name,
+// e.g. t.a in a CHECK on t, to plain column names, so that a stored CHECK
+// keeps working when the table or its columns are renamed.
+func unqualifyColumns(expression *ast.TExpression, table string) {
+	expression.Walk(func(expression *ast.TExpression) bool {
+		if expression.Type == ast.QualifiedType && expression.Qualified.Table.Value == table {
+			column := expression.Qualified.Column
+			*expression = ast.TExpression{Literal: &column, Type: ast.LiteralType}
+		}
+
+		return true
+	})
+}
+
+func (table *TTable) addConstraint(constraint *TConstraint, query *tQuery) error {
+	switch constraint.Type {
+	case ast.PrimaryKeyConstraint, ast.UniqueConstraint:
+		if constraint.Type == ast.PrimaryKeyConstraint {
+			for _, other := range table.Constraints {
+				if other.Type == ast.PrimaryKeyConstraint {
+					return fmt.Errorf("%w: multiple primary keys for table %s", ErrInvalidConstraint, table.Name)
+				}
+			}
+		}
+
+		for _, name := range constraint.Columns {
+			idx, err := table.columnIndex(name)
+			if err != nil {
+				return err
+			}
+
+			if constraint.Type == ast.PrimaryKeyConstraint {
+				column := *table.Columns[idx]
+				column.NotNull = true
+				table.Columns[idx] = &column
+			}
+		}
+	case ast.CheckConstraint:
+		unqualifyColumns(constraint.Check, table.Name)
+		constraint.Columns = referencedColumns(constraint.Check)
+
+		if err := query.rejectAggregates(constraint.Check, "CHECK constraints"); err != nil {
+			return err
+		}
+
+		if err := query.rejectVolatile(constraint.Check, "CHECK constraints"); err != nil {
+			return err
+		}
+
+		if err := rejectSubqueries(constraint.Check, "CHECK constraints"); err != nil {
+			return err
+		}
+
+		if err := checkCondition(constraint.Check, &tRowContext{columns: relationOf(table).columns, query: query}); err != nil {
+			return err
+		}
+	}
+
+	table.Constraints = append(table.Constraints, constraint)
+
+	return nil
+}
+
+func (table *TTable) validateRow(row []TValue, query *tQuery) error {
+	for i, column := range table.Columns {
+		if column.NotNull && row[i] == nil {
+			return fmt.Errorf(
+				"%w: null value in column %s.%s violates NOT NULL",
+				ErrConstraintViolation,
+				table.Name,
+				column.Name,
+			)
+		}
+	}
+
+	var columns []*tRelationColumn
+
+	for _, constraint := range table.Constraints {
+		if constraint.Type != ast.CheckConstraint {
+			continue
+		}
+
+		if columns == nil {
+			columns = relationOf(table).columns
+		}
+
+		value, err := evaluateExpression(constraint.Check, &tRowContext{columns: columns, row: row, query: query})
+		if err != nil {
+			return err
+		}
+
+		if value == false {
+			return fmt.Errorf(
+				"%w: row violates CHECK on %s(%s)",
+				ErrConstraintViolation,
+				table.Name,
+				strings.Join(constraint.Columns, ", "),
+			)
+		}
+	}
+
+	return nil
+}
+
+// uniqueKey encodes the values a PRIMARY KEY or UNIQUE constraint covers in
+// the row. Rows with a NULL in any of them never conflict and have no key.
+func (table *TTable) uniqueKey(constraint *TConstraint, row []TValue) (string, []TValue, bool) {
+	values := make([]TValue, len(constraint.Columns))
+
+	for i, name := range constraint.Columns {
+		idx, _ := table.columnIndex(name)
+		if row[idx] == nil {
+			return "", nil, false
+		}
+
+		values[i] = row[idx]
+	}
+
+	return rowKey(values), values, true
+}
+
+func (table *TTable) duplicateKey(constraint *TConstraint, key []TValue) error {
+	values := make([]string, len(key))
+	for i, value := range key {
+		values[i] = formatText(value)
+	}
+
+	return fmt.Errorf(
+		"%w: duplicate key (%s)=(%s) violates %s on %s",
+		ErrConstraintViolation,
+		strings.Join(constraint.Columns, ", "),
+		strings.Join(values, ", "),
+		constraint.Type,
+		table.Name,
+	)
+}
+
+// uniqueIndex returns the keys of the table's rows for a PRIMARY KEY or
+// UNIQUE constraint, building the index on first use.
+func (table *TTable) uniqueIndex(constraint *TConstraint) (map[string]bool, error) {
+	if index, ok := table.indexes[constraint]; ok {
+		return index, nil
+	}
+
+	index := map[string]bool{}
+
+	for _, row := range table.Rows {
+		key, values, ok := table.uniqueKey(constraint, row)
+		if !ok {
+			continue
+		}
+
+		if index[key] {
+			return nil, table.duplicateKey(constraint, values)
+		}
+
+		index[key] = true
+	}
+
+	if table.indexes == nil {
+		table.indexes = map[*TConstraint]map[string]bool{}
+	}
+	table.indexes[constraint] = index
+
+	return index, nil
+}
+
+// validateUnique checks every row against the PRIMARY KEY and UNIQUE
+// constraints.
+func (table *TTable) validateUnique() error {
+	table.indexes = nil
+
+	for _, constraint := range table.Constraints {
+		if constraint.Type == ast.CheckConstraint {
+			continue
+		}
+
+		if _, err := table.uniqueIndex(constraint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexRows checks that replacing the removed rows of the table with the
+// added ones keeps the PRIMARY KEY and UNIQUE constraints satisfied, and then
+// updates their indexes. Only the rows that change are looked at.
+func (table *TTable) indexRows(removed [][]TValue, added [][]TValue) error {
+	type tIndexChange struct {
+		index   map[string]bool
+		removed []string
+		added   []string
+	}
+
+	changes := []*tIndexChange{}
+
+	for _, constraint := range table.Constraints {
+		if constraint.Type == ast.CheckConstraint {
+			continue
+		}
+
+		index, err := table.uniqueIndex(constraint)
+		if err != nil {
+			return err
+		}
+
+		change := &tIndexChange{index: index}
+		freed := map[string]bool{}
+		taken := map[string]bool{}
+
+		for _, row := range removed {
+			if key, _, ok := table.uniqueKey(constraint, row); ok {
+				freed[key] = true
+				change.removed = append(change.removed, key)
+			}
+		}
+
+		for _, row := range added {
+			key, values, ok := table.uniqueKey(constraint, row)
+			if !ok {
+				continue
+			}
+
+			if (index[key] && !freed[key]) || taken[key] {
+				return table.duplicateKey(constraint, values)
+			}
+
+			taken[key] = true
+			change.added = append(change.added, key)
+		}
+
+		changes = append(changes, change)
+	}
+
+	for _, change := range changes {
+		for _, key := range change.removed {
+			delete(change.index, key)
+		}
+
+		for _, key := range change.added {
+			change.index[key] = true
+		}
+	}
+
+	return nil
+}
+
+func (table *TTable) dropColumnConstraints(name string) {
+	constraints := []*TConstraint{}
+
+	for _, constraint := range table.Constraints {
+		if !constraint.references(name) {
+			constraints = append(constraints, constraint)
+		}
+	}
+
+	table.Constraints = constraints
+}
+
+func (table *TTable) renameColumnConstraints(name string, newName string) {
+	for _, constraint := range table.Constraints {
+		for i, column := range constraint.Columns {
+			if column == name {
+				constraint.Columns[i] = newName
+			}
+		}
+
+		constraint.Check.Walk(func(expression *ast.TExpression) bool {
+			if expression.Type == ast.LiteralType &&
+				expression.Literal.Type == lexer.IdentifierType &&
+				expression.Literal.Value == name {
+				renamed := *expression.Literal
+				renamed.Value = newName
+				expression.Literal = &renamed
+			}
+
+			return true
+		})
+	}
+}
+
+func (constraint *TConstraint) references(name string) bool {
+	for _, column := range constraint.Columns {
+		if column == name {
+			return true
+		}
+	}
+
+	return false
+}
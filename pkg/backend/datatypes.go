@@ -0,0 +1,572 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+const (
+	dateLayout      = "2006-01-02"
+	timestampLayout = "2006-01-02 15:04:05.999999"
+	blobPrefix      = "\\x"
+)
+
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	time.RFC3339Nano,
+	dateLayout,
+}
+
+func (columnType EColumnType) String() string {
+	switch columnType {
+	case IntType:
+		return "INT"
+	case TextType:
+		return "TEXT"
+	case BoolType:
+		return "BOOLEAN"
+	case SmallIntType:
+		return "SMALLINT"
+	case BigIntType:
+		return "BIGINT"
+	case RealType:
+		return "REAL"
+	case DoubleType:
+		return "DOUBLE PRECISION"
+	case NumericType:
+		return "NUMERIC"
+	case VarcharType:
+		return "VARCHAR"
+	case DateType:
+		return "DATE"
+	case TimestampType:
+		return "TIMESTAMP"
+	case BlobType:
+		return "BLOB"
+	case UnknownType:
+		return "UNKNOWN"
+	}
+
+	return "UNKNOWN"
+}
+
+func (columnType EColumnType) IsNumeric() bool {
+	return isIntegerType(columnType) || isFloatType(columnType) || columnType == NumericType
+}
+
+func (column *TColumn) TypeName() string {
+	switch {
+	case column.Type == VarcharType && column.Length > 0:
+		return fmt.Sprintf("%s(%d)", column.Type, column.Length)
+	case column.Type == NumericType && column.Precision > 0:
+		return fmt.Sprintf("%s(%d,%d)", column.Type, column.Precision, column.Scale)
+	}
+
+	return column.Type.String()
+}
+
+func isIntegerType(columnType EColumnType) bool {
+	return columnType == SmallIntType || columnType == IntType || columnType == BigIntType
+}
+
+func isFloatType(columnType EColumnType) bool {
+	return columnType == RealType || columnType == DoubleType
+}
+
+func isTextType(columnType EColumnType) bool {
+	return columnType == TextType || columnType == VarcharType
+}
+
+func isBooleanType(columnType EColumnType) bool {
+	return columnType == BoolType || columnType == UnknownType
+}
+
+func isTimeType(columnType EColumnType) bool {
+	return columnType == DateType || columnType == TimestampType
+}
+
+func integerRange(columnType EColumnType) (int64, int64) {
+	switch columnType {
+	case SmallIntType:
+		return math.MinInt16, math.MaxInt16
+	case IntType:
+		return math.MinInt32, math.MaxInt32
+	}
+
+	return math.MinInt64, math.MaxInt64
+}
+
+func widerInteger(a EColumnType, b EColumnType) EColumnType {
+	if a == BigIntType || b == BigIntType {
+		return BigIntType
+	}
+
+	if a == IntType || b == IntType {
+		return IntType
+	}
+
+	return SmallIntType
+}
+
+func valueType(value TValue) EColumnType {
+	switch value.(type) {
+	case int64:
+		return IntType
+	case float64:
+		return DoubleType
+	case TNumeric:
+		return NumericType
+	case bool:
+		return BoolType
+	case time.Time:
+		return TimestampType
+	case []byte:
+		return BlobType
+	}
+
+	return TextType
+}
+
+func datatypeOf(columnMeta *ast.TColumnMeta) (*TColumn, error) {
+	column := &TColumn{Name: columnMeta.Name.Value}
+
+	switch lexer.TReservedToken(columnMeta.Datatype.Value) {
+	case lexer.IntToken, lexer.IntegerToken:
+		column.Type = IntType
+	case lexer.SmallIntToken:
+		column.Type = SmallIntType
+	case lexer.BigIntToken:
+		column.Type = BigIntType
+	case lexer.TextToken:
+		column.Type = TextType
+	case lexer.VarcharToken:
+		column.Type = VarcharType
+	case lexer.BooleanToken:
+		column.Type = BoolType
+	case lexer.RealToken:
+		column.Type = RealType
+	case lexer.DoubleToken:
+		column.Type = DoubleType
+	case lexer.NumericToken, lexer.DecimalToken:
+		column.Type = NumericType
+	case lexer.DateToken:
+		column.Type = DateType
+	case lexer.TimestampToken:
+		column.Type = TimestampType
+	case lexer.BlobToken:
+		column.Type = BlobType
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDatatype, columnMeta.Datatype.Value)
+	}
+
+	params := make([]uint, len(columnMeta.DatatypeParams))
+	for i, param := range columnMeta.DatatypeParams {
+		value, err := strconv.ParseUint(param.Value, 10, 32)
+		if err != nil || (i == 0 && value == 0) {
+			return nil, fmt.Errorf("%w: invalid %s parameter %s", ErrInvalidDatatype, column.Type, param.Value)
+		}
+
+		params[i] = uint(value)
+	}
+
+	switch column.Type {
+	case VarcharType:
+		if len(params) > 0 {
+			column.Length = params[0]
+		}
+	case NumericType:
+		if len(params) > 0 {
+			column.Precision = params[0]
+		}
+
+		if len(params) > 1 {
+			column.Scale = params[1]
+		}
+
+		if column.Scale > column.Precision {
+			return nil, fmt.Errorf(
+				"%w: NUMERIC scale %d must not exceed precision %d",
+				ErrInvalidDatatype,
+				column.Scale,
+				column.Precision,
+			)
+		}
+	}
+
+	return column, nil
+}
+
+func (column *TColumn) coerce(value TValue) (TValue, error) {
+	value, err := coerceValue(value, column.Type)
+	if err != nil || value == nil {
+		return value, err
+	}
+
+	switch column.Type {
+	case VarcharType:
+		if length := uint(len([]rune(value.(string)))); column.Length > 0 && length > column.Length {
+			return nil, fmt.Errorf("%w: value too long for %s", ErrOutOfRange, column.TypeName())
+		}
+	case NumericType:
+		if column.Precision == 0 {
+			break
+		}
+
+		number := roundRat(value.(TNumeric).rat(), int(column.Scale))
+		limit := new(big.Rat).SetInt(new(big.Int).Exp(bigTen, big.NewInt(int64(column.Precision-column.Scale)), nil))
+
+		if new(big.Rat).Abs(number).Cmp(limit) >= 0 {
+			return nil, fmt.Errorf("%w: %s does not fit %s", ErrOutOfRange, formatText(value), column.TypeName())
+		}
+
+		return numericOf(number), nil
+	}
+
+	return value, nil
+}
+
+func coerceValue(value TValue, columnType EColumnType) (TValue, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch {
+	case isIntegerType(columnType):
+		var number int64
+
+		switch v := value.(type) {
+		case int64:
+			number = v
+		case float64:
+			if math.IsNaN(v) || math.Abs(v) >= math.MaxInt64 {
+				return nil, fmt.Errorf("%w: %s does not fit %s", ErrOutOfRange, formatText(v), columnType)
+			}
+
+			number = int64(math.Round(v))
+		case TNumeric:
+			rounded := roundRat(v.rat(), 0).Num()
+			if !rounded.IsInt64() {
+				return nil, fmt.Errorf("%w: %s does not fit %s", ErrOutOfRange, v, columnType)
+			}
+
+			number = rounded.Int64()
+		case string:
+			parsed, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not %s", ErrInvalidValue, v, columnType)
+			}
+
+			number = parsed
+		default:
+			return nil, invalidCoercion(value, columnType)
+		}
+
+		if min, max := integerRange(columnType); number < min || number > max {
+			return nil, fmt.Errorf("%w: %d does not fit %s", ErrOutOfRange, number, columnType)
+		}
+
+		return number, nil
+	case columnType == NumericType:
+		switch v := value.(type) {
+		case int64:
+			return TNumeric(strconv.FormatInt(v, 10)), nil
+		case float64:
+			return floatNumeric(v)
+		case TNumeric:
+			return v, nil
+		case string:
+			return parseNumeric(v)
+		}
+
+		return nil, invalidCoercion(value, columnType)
+	case isFloatType(columnType):
+		var number float64
+
+		switch v := value.(type) {
+		case int64:
+			number = float64(v)
+		case float64:
+			number = v
+		case TNumeric:
+			number = v.float()
+			if math.IsInf(number, 0) {
+				return nil, fmt.Errorf("%w: %s does not fit %s", ErrOutOfRange, v, columnType)
+			}
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not %s", ErrInvalidValue, v, columnType)
+			}
+
+			number = parsed
+		default:
+			return nil, invalidCoercion(value, columnType)
+		}
+
+		if columnType == RealType {
+			if math.Abs(number) > math.MaxFloat32 && !math.IsInf(number, 0) {
+				return nil, fmt.Errorf("%w: %s does not fit %s", ErrOutOfRange, formatText(number), columnType)
+			}
+
+			number = float64(float32(number))
+		}
+
+		return number, nil
+	case isTextType(columnType):
+		return formatText(value), nil
+	case columnType == BoolType:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			switch strings.ToLower(strings.TrimSpace(v)) {
+			case "t", "true", "y", "yes", "on", "1":
+				return true, nil
+			case "f", "false", "n", "no", "off", "0":
+				return false, nil
+			}
+
+			return nil, fmt.Errorf("%w: %q is not %s", ErrInvalidValue, v, columnType)
+		}
+	case isTimeType(columnType):
+		var moment time.Time
+
+		switch v := value.(type) {
+		case time.Time:
+			moment = v
+		case string:
+			parsed, err := parseTimestamp(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not %s", ErrInvalidValue, v, columnType)
+			}
+
+			moment = parsed
+		default:
+			return nil, invalidCoercion(value, columnType)
+		}
+
+		if columnType == DateType {
+			year, month, day := moment.Date()
+			moment = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		}
+
+		return moment, nil
+	case columnType == BlobType:
+		switch v := value.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			if strings.HasPrefix(v, blobPrefix) {
+				decoded, err := hex.DecodeString(v[len(blobPrefix):])
+				if err != nil {
+					return nil, fmt.Errorf("%w: %q is not %s", ErrInvalidValue, v, columnType)
+				}
+
+				return decoded, nil
+			}
+
+			return []byte(v), nil
+		}
+	}
+
+	return nil, invalidCoercion(value, columnType)
+}
+
+func invalidCoercion(value TValue, columnType EColumnType) error {
+	return fmt.Errorf("%w: cannot store %s as %s", ErrInvalidValue, valueType(value), columnType)
+}
+
+func parseTimestamp(source string) (time.Time, error) {
+	source = strings.TrimSpace(source)
+
+	for _, layout := range timestampLayouts {
+		if moment, err := time.Parse(layout, source); err == nil {
+			return moment.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q is not a timestamp", ErrInvalidValue, source)
+}
+
+func FormatValue(value TValue, columnType EColumnType) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return formatFloat(v, columnType)
+	case TNumeric:
+		return string(v)
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case time.Time:
+		if columnType == DateType {
+			return v.Format(dateLayout)
+		}
+		return v.Format(timestampLayout)
+	case []byte:
+		return blobPrefix + hex.EncodeToString(v)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// formatFloat writes the shortest text that reads back as the same REAL or
+// DOUBLE, in exponent notation once the magnitude needs more digits than the
+// type holds.
+func formatFloat(number float64, columnType EColumnType) string {
+	bitSize, digits := 64, 15
+	if columnType == RealType {
+		bitSize, digits = 32, 6
+	}
+
+	if magnitude := math.Abs(number); magnitude != 0 && (magnitude < 1e-4 || magnitude >= math.Pow10(digits)) {
+		return strconv.FormatFloat(number, 'g', -1, bitSize)
+	}
+
+	return strconv.FormatFloat(number, 'f', -1, bitSize)
+}
+
+func formatText(value TValue) string {
+	return FormatValue(value, valueType(value))
+}
+
+func compareValues(a TValue, b TValue) (int, error) {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrdered(x, y), nil
+		case float64:
+			return compareOrdered(float64(x), y), nil
+		case TNumeric:
+			return new(big.Rat).SetInt64(x).Cmp(y.rat()), nil
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrdered(x, float64(y)), nil
+		case float64:
+			return compareOrdered(x, y), nil
+		case TNumeric:
+			return compareOrdered(x, y.float()), nil
+		}
+	case TNumeric:
+		switch y := b.(type) {
+		case int64:
+			return x.rat().Cmp(new(big.Rat).SetInt64(y)), nil
+		case float64:
+			return compareOrdered(x.float(), y), nil
+		case TNumeric:
+			return x.rat().Cmp(y.rat()), nil
+		}
+	case string:
+		switch y := b.(type) {
+		case string:
+			return strings.Compare(x, y), nil
+		case time.Time:
+			moment, err := parseTimestamp(x)
+			if err != nil {
+				return 0, err
+			}
+
+			return moment.Compare(y), nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			if x == y {
+				return 0, nil
+			} else if !x {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		switch y := b.(type) {
+		case time.Time:
+			return x.Compare(y), nil
+		case string:
+			moment, err := parseTimestamp(y)
+			if err != nil {
+				return 0, err
+			}
+
+			return x.Compare(moment), nil
+		}
+	case []byte:
+		if y, ok := b.([]byte); ok {
+			return bytes.Compare(x, y), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: cannot compare %s with %s", ErrTypeMismatch, valueType(a), valueType(b))
+}
+
+func compareOrdered[T int64 | float64](a T, b T) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+func comparableTypes(a EColumnType, b EColumnType) bool {
+	switch {
+	case a == b || a == UnknownType || b == UnknownType:
+		return true
+	case a.IsNumeric() && b.IsNumeric():
+		return true
+	case isTextType(a) && isTextType(b):
+		return true
+	case isTimeType(a) && (isTimeType(b) || isTextType(b)):
+		return true
+	case isTextType(a) && isTimeType(b):
+		return true
+	}
+
+	return false
+}
+
+// commonType returns the type that values of both types are reported as when
+// they end up in one column, e.g. in the branches of a CASE.
+func commonType(a EColumnType, b EColumnType) (EColumnType, bool) {
+	switch {
+	case a == UnknownType:
+		return b, true
+	case b == UnknownType || a == b:
+		return a, true
+	case isIntegerType(a) && isIntegerType(b):
+		return widerInteger(a, b), true
+	case a.IsNumeric() && b.IsNumeric():
+		if isFloatType(a) || isFloatType(b) {
+			return DoubleType, true
+		}
+
+		return NumericType, true
+	case isTextType(a) && isTextType(b):
+		return TextType, true
+	case isTimeType(a) && isTimeType(b):
+		return TimestampType, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,730 @@
+package backend
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+func ruleName(rule *ast.TSelectRule) string {
+	if rule.Alias != nil {
+		return rule.Alias.Value
+	}
+
+	if _, name, ok := columnReference(rule.Expression); ok {
+		return name
+	}
+
+	switch rule.Expression.Type {
+	case ast.FunctionType:
+		return rule.Expression.Function.Name.Value
+	case ast.ExistsType:
+		return string(lexer.ExistsToken)
+	case ast.CaseType:
+		return string(lexer.CaseToken)
+	case ast.SubqueryType:
+		subquery := rule.Expression.Subquery
+		for subquery.Compound != nil {
+			subquery = subquery.Compound.Left
+		}
+
+		if rules := subquery.Rules; len(rules) == 1 && !rules[0].Star {
+			return ruleName(rules[0])
+		}
+	case ast.CastType:
+		if name := ruleName(&ast.TSelectRule{Expression: rule.Expression.Cast.Operand}); name != "?column?" {
+			return name
+		}
+
+		return rule.Expression.Cast.Datatype.Value
+	}
+
+	return "?column?"
+}
+
+// expandRules replaces * and t.* with references to the matching source
+// columns in declared order. Columns merged away by USING only show up in t.*.
+func expandRules(rules []*ast.TSelectRule, columns []*tRelationColumn) ([]*ast.TSelectRule, error) {
+	expanded := []*ast.TSelectRule{}
+
+	for _, rule := range rules {
+		if !rule.Star {
+			expanded = append(expanded, rule)
+			continue
+		}
+
+		if rule.Table == nil && len(columns) == 0 {
+			return nil, fmt.Errorf("%w: SELECT * with no tables specified", ErrInvalidStatement)
+		}
+
+		found := false
+
+		for _, column := range columns {
+			if (rule.Table != nil && column.table != rule.Table.Value) || (rule.Table == nil && column.hidden) {
+				continue
+			}
+
+			found = true
+			expanded = append(expanded, &ast.TSelectRule{Expression: columnExpression(column)})
+		}
+
+		if rule.Table != nil && !found {
+			return nil, fmt.Errorf("%w: %s", ErrTableDoesNotExist, rule.Table.Value)
+		}
+	}
+
+	return expanded, nil
+}
+
+func columnExpression(column *tRelationColumn) *ast.TExpression {
+	name := lexer.TToken{Value: column.name, Type: lexer.IdentifierType}
+
+	if column.table == "" {
+		return &ast.TExpression{Literal: &name, Type: ast.LiteralType}
+	}
+
+	return &ast.TExpression{
+		Qualified: &ast.TQualifiedColumn{
+			Table:  lexer.TToken{Value: column.table, Type: lexer.IdentifierType},
+			Column: name,
+		},
+		Type: ast.QualifiedType,
+	}
+}
+
+func expressionType(expression *ast.TExpression, scope *tRowContext) (EColumnType, error) {
+	if table, name, ok := columnReference(expression); ok {
+		scope, idx, err := scope.lookup(table, name)
+		if err != nil {
+			return 0, err
+		}
+
+		return scope.columns[idx].typ, nil
+	}
+
+	switch expression.Type {
+	case ast.LiteralType:
+		switch expression.Literal.Type {
+		case lexer.NumericType:
+			return numericLiteralType(expression.Literal.Value), nil
+		case lexer.StringType:
+			return TextType, nil
+		case lexer.BooleanType:
+			return BoolType, nil
+		case lexer.NullType:
+			return UnknownType, nil
+		}
+	case ast.BinaryType:
+		a, err := expressionType(expression.Binary.A, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		b, err := expressionType(expression.Binary.B, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		return binaryOperatorType(expression.Binary.Operator.Value, a, b)
+	case ast.UnaryType:
+		operand, err := expressionType(expression.Unary.Operand, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		return unaryOperatorType(expression.Unary.Operator.Value, operand)
+	case ast.IsType:
+		a, err := expressionType(expression.Is.A, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		b, err := expressionType(expression.Is.B, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		if !comparableTypes(a, b) {
+			return 0, fmt.Errorf("%w: cannot compare %s with %s", ErrTypeMismatch, a, b)
+		}
+
+		return BoolType, nil
+	case ast.FunctionType:
+		if scope.query.isAggregate(expression) {
+			return aggregateType(expression.Function, scope)
+		}
+
+		return scalarType(expression.Function, scope)
+	case ast.SubqueryType, ast.ExistsType:
+		return subqueryType(expression, scope)
+	case ast.InType:
+		return inType(expression.In, scope)
+	case ast.BetweenType:
+		return betweenType(expression.Between, scope)
+	case ast.LikeType:
+		return likeType(expression.Like, scope)
+	case ast.CaseType:
+		return caseType(expression.Case, scope)
+	case ast.CastType:
+		return castType(expression.Cast, scope)
+	}
+
+	return 0, fmt.Errorf("Unsupported expression")
+}
+
+func binaryOperatorType(operator string, a EColumnType, b EColumnType) (EColumnType, error) {
+	if a == UnknownType {
+		a = b
+	} else if b == UnknownType {
+		b = a
+	}
+
+	switch {
+	case isArithmeticOperator(operator):
+		if columnType, ok := arithmeticType(operator, a, b); ok {
+			return columnType, nil
+		}
+	case operator == string(lexer.ConcatToken):
+		return TextType, nil
+	case isComparisonOperator(operator):
+		if comparableTypes(a, b) {
+			return BoolType, nil
+		}
+
+		return 0, fmt.Errorf("%w: cannot compare %s with %s", ErrTypeMismatch, a, b)
+	case isLogicalOperator(operator):
+		if isBooleanType(a) && isBooleanType(b) {
+			return BoolType, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: operator %s is not defined for %s and %s", ErrTypeMismatch, strings.ToUpper(operator), a, b)
+}
+
+func arithmeticType(operator string, a EColumnType, b EColumnType) (EColumnType, bool) {
+	additive := operator == string(lexer.PlusToken) || operator == string(lexer.MinusToken)
+
+	switch {
+	case a == UnknownType && b == UnknownType:
+		return UnknownType, true
+	case isIntegerType(a) && isIntegerType(b):
+		return widerInteger(a, b), true
+	case a.IsNumeric() && b.IsNumeric():
+		if isFloatType(a) || isFloatType(b) {
+			return DoubleType, true
+		}
+
+		return NumericType, true
+	case a == DateType && isIntegerType(b) && additive:
+		return DateType, true
+	case isIntegerType(a) && b == DateType && operator == string(lexer.PlusToken):
+		return DateType, true
+	case a == DateType && b == DateType && operator == string(lexer.MinusToken):
+		return IntType, true
+	}
+
+	return 0, false
+}
+
+func unaryOperatorType(operator string, operand EColumnType) (EColumnType, error) {
+	switch lexer.TSymbolToken(operator) {
+	case lexer.MinusToken, lexer.PlusToken:
+		if operand.IsNumeric() || operand == UnknownType {
+			return operand, nil
+		}
+	default:
+		if isBooleanType(operand) {
+			return BoolType, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: operator %s is not defined for %s", ErrTypeMismatch, strings.ToUpper(operator), operand)
+}
+
+func checkCondition(condition *ast.TExpression, scope *tRowContext) error {
+	if condition == nil {
+		return nil
+	}
+
+	conditionType, err := expressionType(condition, scope)
+	if err != nil {
+		return err
+	}
+
+	if !isBooleanType(conditionType) {
+		return fmt.Errorf("%w: condition must be BOOLEAN, not %s", ErrTypeMismatch, conditionType)
+	}
+
+	return nil
+}
+
+func evaluateCondition(condition *ast.TExpression, ctx *tRowContext) (bool, error) {
+	if condition == nil {
+		return true, nil
+	}
+
+	value, err := evaluateExpression(condition, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return value == true, nil
+}
+
+func filterRelation(relation *tRelation, condition *ast.TExpression, query *tQuery) (*tRelation, error) {
+	if condition == nil {
+		return relation, nil
+	}
+
+	if err := query.rejectAggregates(condition, "WHERE"); err != nil {
+		return nil, err
+	}
+
+	if err := checkCondition(condition, &tRowContext{columns: relation.columns, query: query}); err != nil {
+		return nil, err
+	}
+
+	filtered := &tRelation{columns: relation.columns}
+
+	for _, row := range relation.rows {
+		matches, err := evaluateCondition(condition, &tRowContext{columns: relation.columns, row: row, query: query})
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			filtered.rows = append(filtered.rows, row)
+		}
+	}
+
+	return filtered, nil
+}
+
+func evaluateExpression(expression *ast.TExpression, ctx *tRowContext) (TValue, error) {
+	if table, name, ok := columnReference(expression); ok {
+		ctx, idx, err := ctx.lookup(table, name)
+		if err != nil {
+			return nil, err
+		}
+
+		return ctx.row[idx], nil
+	}
+
+	switch expression.Type {
+	case ast.LiteralType:
+		return evaluateLiteral(expression.Literal, ctx)
+	case ast.BinaryType:
+		return evaluateBinary(expression.Binary, ctx)
+	case ast.UnaryType:
+		return evaluateUnary(expression.Unary, ctx)
+	case ast.IsType:
+		return evaluateIs(expression.Is, ctx)
+	case ast.FunctionType:
+		return evaluateFunction(expression.Function, ctx)
+	case ast.SubqueryType, ast.ExistsType:
+		return evaluateSubquery(expression, ctx)
+	case ast.InType:
+		return evaluateIn(expression.In, ctx)
+	case ast.BetweenType:
+		return evaluateBetween(expression.Between, ctx)
+	case ast.LikeType:
+		return evaluateLike(expression.Like, ctx)
+	case ast.CaseType:
+		return evaluateCase(expression.Case, ctx)
+	case ast.CastType:
+		return evaluateCast(expression.Cast, ctx)
+	}
+
+	return nil, fmt.Errorf("Unsupported expression")
+}
+
+func evaluateFunction(call *ast.TFunctionCall, ctx *tRowContext) (TValue, error) {
+	if function, ok := ctx.query.mb.scalarFunction(call.Name.Value); ok {
+		return evaluateScalar(function, call, ctx)
+	}
+
+	if _, ok := ctx.query.mb.aggregateFunction(call.Name.Value); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFunctionDoesNotExist, call.Name.Value)
+	}
+
+	value, ok := ctx.aggregates[call]
+	if !ok {
+		return nil, fmt.Errorf("%w: aggregate functions are not allowed here", ErrInvalidGrouping)
+	}
+
+	return value, nil
+}
+
+func evaluateLiteral(token *lexer.TToken, ctx *tRowContext) (TValue, error) {
+	switch token.Type {
+	case lexer.NumericType:
+		if value, err := strconv.ParseInt(token.Value, 10, 64); err == nil {
+			return value, nil
+		}
+
+		return parseNumeric(token.Value)
+	case lexer.StringType:
+		return token.Value, nil
+	case lexer.BooleanType:
+		return token.Value == string(lexer.TrueToken), nil
+	case lexer.NullType:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("%w: unexpected literal %s", ErrInvalidValue, token.Value)
+}
+
+func evaluateBinary(expression *ast.TBinaryExpression, ctx *tRowContext) (TValue, error) {
+	a, err := evaluateExpression(expression.A, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := evaluateExpression(expression.B, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	operator := expression.Operator.Value
+
+	if isLogicalOperator(operator) {
+		return applyLogical(operator, a, b)
+	}
+
+	if a == nil || b == nil {
+		return nil, nil
+	}
+
+	switch {
+	case isArithmeticOperator(operator):
+		result, err := applyArithmetic(operator, a, b)
+		if err != nil {
+			return nil, err
+		}
+
+		if number, ok := result.(int64); ok {
+			return checkIntegerResult(number, expression, ctx)
+		}
+
+		return result, nil
+	case operator == string(lexer.ConcatToken):
+		return formatText(a) + formatText(b), nil
+	case isComparisonOperator(operator):
+		cmp, err := compareValues(a, b)
+		if err != nil {
+			return nil, err
+		}
+
+		return applyComparison(operator, cmp), nil
+	}
+
+	return nil, fmt.Errorf(
+		"%w: operator %s is not defined for %s and %s",
+		ErrTypeMismatch,
+		strings.ToUpper(operator),
+		valueType(a),
+		valueType(b),
+	)
+}
+
+// checkIntegerResult keeps integer arithmetic within the type the expression
+// was checked as, e.g. INT + INT must still fit INT.
+func checkIntegerResult(number int64, expression *ast.TBinaryExpression, ctx *tRowContext) (TValue, error) {
+	columnType, ok := ctx.query.types[expression]
+	if !ok {
+		var err error
+
+		columnType, err = expressionType(&ast.TExpression{Type: ast.BinaryType, Binary: expression}, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.query.types[expression] = columnType
+	}
+
+	if min, max := integerRange(columnType); isIntegerType(columnType) && (number < min || number > max) {
+		return nil, fmt.Errorf("%w: %d does not fit %s", ErrOutOfRange, number, columnType)
+	}
+
+	return number, nil
+}
+
+func evaluateUnary(expression *ast.TUnaryExpression, ctx *tRowContext) (TValue, error) {
+	operand, err := evaluateExpression(expression.Operand, ctx)
+	if err != nil || operand == nil {
+		return nil, err
+	}
+
+	operator := expression.Operator.Value
+
+	switch v := operand.(type) {
+	case int64:
+		switch lexer.TSymbolToken(operator) {
+		case lexer.MinusToken:
+			if v == math.MinInt64 {
+				return nil, fmt.Errorf("%w: -%d does not fit BIGINT", ErrOutOfRange, v)
+			}
+
+			return -v, nil
+		case lexer.PlusToken:
+			return v, nil
+		}
+	case float64:
+		switch lexer.TSymbolToken(operator) {
+		case lexer.MinusToken:
+			return -v, nil
+		case lexer.PlusToken:
+			return v, nil
+		}
+	case TNumeric:
+		switch lexer.TSymbolToken(operator) {
+		case lexer.MinusToken:
+			return numericOf(new(big.Rat).Neg(v.rat())), nil
+		case lexer.PlusToken:
+			return v, nil
+		}
+	case bool:
+		if operator == string(lexer.NotToken) {
+			return !v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: operator %s is not defined for %s", ErrTypeMismatch, strings.ToUpper(operator), valueType(operand))
+}
+
+func evaluateIs(expression *ast.TIsExpression, ctx *tRowContext) (TValue, error) {
+	a, err := evaluateExpression(expression.A, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := evaluateExpression(expression.B, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	distinct, err := distinctValues(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return distinct == expression.Distinct != expression.Not, nil
+}
+
+func distinctValues(a TValue, b TValue) (bool, error) {
+	if a == nil || b == nil {
+		return a != b, nil
+	}
+
+	cmp, err := compareValues(a, b)
+	if err != nil {
+		return false, err
+	}
+
+	return cmp != 0, nil
+}
+
+func applyLogical(operator string, a TValue, b TValue) (TValue, error) {
+	x, okA := a.(bool)
+	y, okB := b.(bool)
+
+	if (!okA && a != nil) || (!okB && b != nil) {
+		return nil, fmt.Errorf(
+			"%w: operator %s is not defined for %s and %s",
+			ErrTypeMismatch,
+			strings.ToUpper(operator),
+			valueType(a),
+			valueType(b),
+		)
+	}
+
+	// Three-valued logic: a known operand may decide the result even when
+	// the other one is NULL.
+	decisive := operator == string(lexer.OrToken)
+	if (okA && x == decisive) || (okB && y == decisive) {
+		return decisive, nil
+	}
+
+	if a == nil || b == nil {
+		return nil, nil
+	}
+
+	return !decisive, nil
+}
+
+func applyArithmetic(operator string, a TValue, b TValue) (TValue, error) {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return integerArithmetic(operator, x, y)
+		case float64:
+			return floatArithmetic(operator, float64(x), y)
+		case TNumeric:
+			return numericArithmetic(operator, new(big.Rat).SetInt64(x), y.rat())
+		case time.Time:
+			if operator == string(lexer.PlusToken) {
+				return y.AddDate(0, 0, int(x)), nil
+			}
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return floatArithmetic(operator, x, float64(y))
+		case float64:
+			return floatArithmetic(operator, x, y)
+		case TNumeric:
+			return floatArithmetic(operator, x, y.float())
+		}
+	case TNumeric:
+		switch y := b.(type) {
+		case int64:
+			return numericArithmetic(operator, x.rat(), new(big.Rat).SetInt64(y))
+		case float64:
+			return floatArithmetic(operator, x.float(), y)
+		case TNumeric:
+			return numericArithmetic(operator, x.rat(), y.rat())
+		}
+	case time.Time:
+		switch y := b.(type) {
+		case int64:
+			switch lexer.TSymbolToken(operator) {
+			case lexer.PlusToken:
+				return x.AddDate(0, 0, int(y)), nil
+			case lexer.MinusToken:
+				return x.AddDate(0, 0, -int(y)), nil
+			}
+		case time.Time:
+			if operator == string(lexer.MinusToken) {
+				return int64(x.Sub(y).Hours() / 24), nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"%w: operator %s is not defined for %s and %s",
+		ErrTypeMismatch,
+		operator,
+		valueType(a),
+		valueType(b),
+	)
+}
+
+func integerArithmetic(operator string, a int64, b int64) (TValue, error) {
+	var res int64
+	overflow := false
+
+	switch lexer.TSymbolToken(operator) {
+	case lexer.PlusToken:
+		res = a + b
+		overflow = (a > 0 && b > 0 && res < 0) || (a < 0 && b < 0 && res >= 0)
+	case lexer.MinusToken:
+		res = a - b
+		overflow = (a >= 0 && b < 0 && res < 0) || (a < 0 && b > 0 && res >= 0)
+	case lexer.AsteriksToken:
+		res = a * b
+		overflow = a != 0 && (res/a != b || (a == -1 && b == math.MinInt64))
+	default:
+		if b == 0 {
+			return nil, ErrDivisionByZero
+		}
+
+		if lexer.TSymbolToken(operator) == lexer.PercentToken {
+			return a % b, nil
+		}
+
+		res = a / b
+		overflow = a == math.MinInt64 && b == -1
+	}
+
+	if overflow {
+		return nil, fmt.Errorf("%w: %d %s %d does not fit BIGINT", ErrOutOfRange, a, operator, b)
+	}
+
+	return res, nil
+}
+
+func floatArithmetic(operator string, a float64, b float64) (TValue, error) {
+	switch lexer.TSymbolToken(operator) {
+	case lexer.PlusToken:
+		return a + b, nil
+	case lexer.MinusToken:
+		return a - b, nil
+	case lexer.AsteriksToken:
+		return a * b, nil
+	}
+
+	if b == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	if lexer.TSymbolToken(operator) == lexer.PercentToken {
+		return math.Mod(a, b), nil
+	}
+
+	return a / b, nil
+}
+
+func numericLiteralType(literal string) EColumnType {
+	value, err := strconv.ParseInt(literal, 10, 64)
+	if err != nil {
+		return NumericType
+	}
+
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return BigIntType
+	}
+
+	return IntType
+}
+
+func applyComparison(operator string, cmp int) bool {
+	switch lexer.TSymbolToken(operator) {
+	case lexer.EqualToken:
+		return cmp == 0
+	case lexer.NotEqualToken, lexer.BangEqualToken:
+		return cmp != 0
+	case lexer.LessToken:
+		return cmp < 0
+	case lexer.LessEqualToken:
+		return cmp <= 0
+	case lexer.GreaterToken:
+		return cmp > 0
+	}
+
+	return cmp >= 0
+}
+
+func isArithmeticOperator(operator string) bool {
+	switch lexer.TSymbolToken(operator) {
+	case lexer.PlusToken, lexer.MinusToken, lexer.AsteriksToken, lexer.SlashToken, lexer.PercentToken:
+		return true
+	}
+
+	return false
+}
+
+func isComparisonOperator(operator string) bool {
+	switch lexer.TSymbolToken(operator) {
+	case lexer.EqualToken, lexer.NotEqualToken, lexer.BangEqualToken,
+		lexer.LessToken, lexer.LessEqualToken, lexer.GreaterToken, lexer.GreaterEqualToken:
+		return true
+	}
+
+	return false
+}
+
+func isLogicalOperator(operator string) bool {
+	switch lexer.TReservedToken(operator) {
+	case lexer.AndToken, lexer.OrToken:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,601 @@
+package backend
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+// tScalarFunction describes a scalar function. A maxArguments of -1 makes
+// the function variadic. Unless callOnNull is set a NULL argument makes the
+// result NULL without calling evaluate. A volatile function may return
+// different results for the same arguments. Functions that read state of the
+// running statement, like now, set queryEvaluate instead of evaluate.
+type tScalarFunction struct {
+	minArguments  int
+	maxArguments  int
+	callOnNull    bool
+	volatile      bool
+	resultType    func(arguments []EColumnType) (EColumnType, bool)
+	evaluate      func(arguments []TValue) (TValue, error)
+	queryEvaluate func(query *tQuery, arguments []TValue) (TValue, error)
+}
+
+var scalarFunctions = map[string]*tScalarFunction{
+	"lower": {
+		minArguments: 1,
+		maxArguments: 1,
+		resultType:   textResult(isTextArgument),
+		evaluate: func(arguments []TValue) (TValue, error) {
+			texts, err := textArguments("lower", arguments)
+			if err != nil {
+				return nil, err
+			}
+
+			return strings.ToLower(texts[0]), nil
+		},
+	},
+	"upper": {
+		minArguments: 1,
+		maxArguments: 1,
+		resultType:   textResult(isTextArgument),
+		evaluate: func(arguments []TValue) (TValue, error) {
+			texts, err := textArguments("upper", arguments)
+			if err != nil {
+				return nil, err
+			}
+
+			return strings.ToUpper(texts[0]), nil
+		},
+	},
+	"length": {
+		minArguments: 1,
+		maxArguments: 1,
+		resultType: func(arguments []EColumnType) (EColumnType, bool) {
+			return IntType, isTextArgument(arguments[0])
+		},
+		evaluate: func(arguments []TValue) (TValue, error) {
+			texts, err := textArguments("length", arguments)
+			if err != nil {
+				return nil, err
+			}
+
+			return int64(utf8.RuneCountInString(texts[0])), nil
+		},
+	},
+	"substr": {
+		minArguments: 2,
+		maxArguments: 3,
+		resultType:   textResult(isTextArgument, isIntegerArgument, isIntegerArgument),
+		evaluate:     substr,
+	},
+	"trim": {
+		minArguments: 1,
+		maxArguments: 2,
+		resultType:   textResult(isTextArgument, isTextArgument),
+		evaluate: func(arguments []TValue) (TValue, error) {
+			texts, err := textArguments("trim", arguments)
+			if err != nil {
+				return nil, err
+			}
+
+			cutset := " "
+			if len(texts) == 2 {
+				cutset = texts[1]
+			}
+
+			return strings.Trim(texts[0], cutset), nil
+		},
+	},
+	"replace": {
+		minArguments: 3,
+		maxArguments: 3,
+		resultType:   textResult(isTextArgument, isTextArgument, isTextArgument),
+		evaluate: func(arguments []TValue) (TValue, error) {
+			texts, err := textArguments("replace", arguments)
+			if err != nil {
+				return nil, err
+			}
+
+			text, from, to := texts[0], texts[1], texts[2]
+			if from == "" {
+				return text, nil
+			}
+
+			return strings.ReplaceAll(text, from, to), nil
+		},
+	},
+	"concat": {
+		minArguments: 1,
+		maxArguments: -1,
+		callOnNull:   true,
+		resultType: func([]EColumnType) (EColumnType, bool) {
+			return TextType, true
+		},
+		evaluate: func(arguments []TValue) (TValue, error) {
+			text := strings.Builder{}
+
+			for _, argument := range arguments {
+				if argument != nil {
+					text.WriteString(formatText(argument))
+				}
+			}
+
+			return text.String(), nil
+		},
+	},
+	"abs": {
+		minArguments: 1,
+		maxArguments: 1,
+		resultType:   sameNumericResult,
+		evaluate: func(arguments []TValue) (TValue, error) {
+			switch x := arguments[0].(type) {
+			case int64:
+				if x == math.MinInt64 {
+					return nil, fmt.Errorf("%w: abs(%d) does not fit BIGINT", ErrOutOfRange, x)
+				}
+
+				if x < 0 {
+					return -x, nil
+				}
+
+				return x, nil
+			case float64:
+				return math.Abs(x), nil
+			case TNumeric:
+				return TNumeric(strings.TrimPrefix(string(x), "-")), nil
+			}
+
+			return nil, invalidArgument("abs", arguments[0])
+		},
+	},
+	"round": {
+		minArguments: 1,
+		maxArguments: 2,
+		resultType: func(arguments []EColumnType) (EColumnType, bool) {
+			if len(arguments) == 1 {
+				return sameNumericResult(arguments)
+			}
+
+			return NumericType, isNumericArgument(arguments[0]) && isIntegerArgument(arguments[1])
+		},
+		evaluate: round,
+	},
+	"floor": {
+		minArguments: 1,
+		maxArguments: 1,
+		resultType:   sameNumericResult,
+		evaluate:     roundingFunction("floor", math.Floor, floorRat),
+	},
+	"ceil": {
+		minArguments: 1,
+		maxArguments: 1,
+		resultType:   sameNumericResult,
+		evaluate:     roundingFunction("ceil", math.Ceil, ceilRat),
+	},
+	"mod": {
+		minArguments: 2,
+		maxArguments: 2,
+		resultType: func(arguments []EColumnType) (EColumnType, bool) {
+			if !isNumericArgument(arguments[0]) || !isNumericArgument(arguments[1]) {
+				return 0, false
+			}
+
+			return arithmeticType(string(lexer.PercentToken), arguments[0], arguments[1])
+		},
+		evaluate: func(arguments []TValue) (TValue, error) {
+			return applyArithmetic(string(lexer.PercentToken), arguments[0], arguments[1])
+		},
+	},
+	"power": {
+		minArguments: 2,
+		maxArguments: 2,
+		resultType: func(arguments []EColumnType) (EColumnType, bool) {
+			return DoubleType, isNumericArgument(arguments[0]) && isNumericArgument(arguments[1])
+		},
+		evaluate: power,
+	},
+	"coalesce": {
+		minArguments: 1,
+		maxArguments: -1,
+		callOnNull:   true,
+		resultType: func(arguments []EColumnType) (EColumnType, bool) {
+			resultType := UnknownType
+
+			for _, argument := range arguments {
+				common, ok := commonType(resultType, argument)
+				if !ok {
+					return 0, false
+				}
+
+				resultType = common
+			}
+
+			return resultType, true
+		},
+		evaluate: func(arguments []TValue) (TValue, error) {
+			for _, argument := range arguments {
+				if argument != nil {
+					return argument, nil
+				}
+			}
+
+			return nil, nil
+		},
+	},
+	"nullif": {
+		minArguments: 2,
+		maxArguments: 2,
+		callOnNull:   true,
+		resultType: func(arguments []EColumnType) (EColumnType, bool) {
+			resultType := arguments[0]
+			if resultType == UnknownType {
+				resultType = arguments[1]
+			}
+
+			return resultType, comparableTypes(arguments[0], arguments[1])
+		},
+		evaluate: func(arguments []TValue) (TValue, error) {
+			if arguments[0] == nil || arguments[1] == nil {
+				return arguments[0], nil
+			}
+
+			cmp, err := compareValues(arguments[0], arguments[1])
+			if err != nil || cmp == 0 {
+				return nil, err
+			}
+
+			return arguments[0], nil
+		},
+	},
+	"now": {
+		volatile: true,
+		resultType: func([]EColumnType) (EColumnType, bool) {
+			return TimestampType, true
+		},
+		queryEvaluate: func(query *tQuery, _ []TValue) (TValue, error) {
+			return query.started, nil
+		},
+	},
+	"date_trunc": {
+		minArguments: 2,
+		maxArguments: 2,
+		resultType: func(arguments []EColumnType) (EColumnType, bool) {
+			return TimestampType, isTextArgument(arguments[0]) && isTimeArgument(arguments[1])
+		},
+		evaluate: dateTrunc,
+	},
+	"extract": {
+		minArguments: 2,
+		maxArguments: 2,
+		resultType: func(arguments []EColumnType) (EColumnType, bool) {
+			return NumericType, isTextArgument(arguments[0]) && isTimeArgument(arguments[1])
+		},
+		evaluate: extract,
+	},
+}
+
+func isTextArgument(argument EColumnType) bool {
+	return isTextType(argument) || argument == UnknownType
+}
+
+func isIntegerArgument(argument EColumnType) bool {
+	return isIntegerType(argument) || argument == UnknownType
+}
+
+func isNumericArgument(argument EColumnType) bool {
+	return argument.IsNumeric() || argument == UnknownType
+}
+
+// isTimeArgument also accepts text, which is parsed as a timestamp.
+func isTimeArgument(argument EColumnType) bool {
+	return isTimeType(argument) || isTextArgument(argument)
+}
+
+func textResult(accepts ...func(EColumnType) bool) func([]EColumnType) (EColumnType, bool) {
+	return func(arguments []EColumnType) (EColumnType, bool) {
+		for i, argument := range arguments {
+			if !accepts[i](argument) {
+				return 0, false
+			}
+		}
+
+		return TextType, true
+	}
+}
+
+func sameNumericResult(arguments []EColumnType) (EColumnType, bool) {
+	switch {
+	case isFloatType(arguments[0]):
+		return DoubleType, true
+	case isNumericArgument(arguments[0]):
+		return arguments[0], true
+	}
+
+	return 0, false
+}
+
+func scalarType(call *ast.TFunctionCall, scope *tRowContext) (EColumnType, error) {
+	name := call.Name.Value
+
+	function, ok := scope.query.mb.scalarFunction(name)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrFunctionDoesNotExist, name)
+	}
+
+	if call.Star {
+		return 0, fmt.Errorf("%w: %s(*)", ErrFunctionDoesNotExist, name)
+	}
+
+	if call.Distinct {
+		return 0, fmt.Errorf("%w: DISTINCT specified, but %s is not an aggregate function", ErrInvalidGrouping, name)
+	}
+
+	arguments := make([]EColumnType, len(call.Arguments))
+
+	for i, argument := range call.Arguments {
+		argumentType, err := expressionType(argument, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		arguments[i] = argumentType
+	}
+
+	arity := len(arguments) >= function.minArguments &&
+		(function.maxArguments < 0 || len(arguments) <= function.maxArguments)
+
+	if arity {
+		if resultType, ok := function.resultType(arguments); ok {
+			return resultType, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrFunctionDoesNotExist, functionSignature(name, arguments))
+}
+
+func evaluateScalar(function *tScalarFunction, call *ast.TFunctionCall, ctx *tRowContext) (TValue, error) {
+	arguments := make([]TValue, len(call.Arguments))
+
+	for i, argument := range call.Arguments {
+		value, err := evaluateExpression(argument, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil && !function.callOnNull {
+			return nil, nil
+		}
+
+		arguments[i] = value
+	}
+
+	if function.queryEvaluate != nil {
+		return function.queryEvaluate(ctx.query, arguments)
+	}
+
+	return function.evaluate(arguments)
+}
+
+func invalidArgument(name string, argument TValue) error {
+	return fmt.Errorf("%w: %s(%s)", ErrFunctionDoesNotExist, name, valueType(argument))
+}
+
+// textArguments returns the arguments of a text function as strings and
+// fails like a call with the wrong argument types if one is not text.
+func textArguments(name string, arguments []TValue) ([]string, error) {
+	texts := make([]string, len(arguments))
+
+	for i, argument := range arguments {
+		text, ok := argument.(string)
+		if !ok {
+			return nil, invalidArgument(name, argument)
+		}
+
+		texts[i] = text
+	}
+
+	return texts, nil
+}
+
+func substr(arguments []TValue) (TValue, error) {
+	text, ok := arguments[0].(string)
+	if !ok {
+		return nil, invalidArgument("substr", arguments[0])
+	}
+
+	start, ok := arguments[1].(int64)
+	if !ok {
+		return nil, invalidArgument("substr", arguments[1])
+	}
+
+	runes := []rune(text)
+	end := int64(len(runes)) + 1
+
+	if len(arguments) == 3 {
+		count, ok := arguments[2].(int64)
+		if !ok {
+			return nil, invalidArgument("substr", arguments[2])
+		}
+
+		if count < 0 {
+			return nil, fmt.Errorf("%w: negative substring length not allowed", ErrInvalidValue)
+		}
+
+		end = min(end, start+min(count, end))
+	}
+
+	from := max(start, 1)
+	if from >= end {
+		return "", nil
+	}
+
+	return string(runes[from-1 : end-1]), nil
+}
+
+func round(arguments []TValue) (TValue, error) {
+	if len(arguments) == 1 {
+		return roundingFunction("round", math.Round, func(x *big.Rat) *big.Rat {
+			return roundRat(x, 0)
+		})(arguments)
+	}
+
+	x, err := coerceValue(arguments[0], NumericType)
+	if err != nil {
+		return nil, err
+	}
+
+	scale, ok := arguments[1].(int64)
+	if !ok {
+		return nil, invalidArgument("round", arguments[1])
+	}
+
+	if scale > numericMaxExponent || scale < -numericMaxExponent {
+		return nil, fmt.Errorf("%w: round scale %d is out of range", ErrOutOfRange, scale)
+	}
+
+	return numericOf(roundRat(x.(TNumeric).rat(), int(scale))), nil
+}
+
+func roundingFunction(
+	name string,
+	apply func(float64) float64,
+	applyNumeric func(*big.Rat) *big.Rat,
+) func([]TValue) (TValue, error) {
+	return func(arguments []TValue) (TValue, error) {
+		switch x := arguments[0].(type) {
+		case int64:
+			return x, nil
+		case float64:
+			return apply(x), nil
+		case TNumeric:
+			return numericOf(applyNumeric(x.rat())), nil
+		}
+
+		return nil, invalidArgument(name, arguments[0])
+	}
+}
+
+func ceilRat(number *big.Rat) *big.Rat {
+	negated := floorRat(new(big.Rat).Neg(number))
+	return negated.Neg(negated)
+}
+
+func power(arguments []TValue) (TValue, error) {
+	operands := make([]float64, 2)
+
+	for i, argument := range arguments {
+		switch v := argument.(type) {
+		case int64:
+			operands[i] = float64(v)
+		case float64:
+			operands[i] = v
+		case TNumeric:
+			operands[i] = v.float()
+		default:
+			return nil, invalidArgument("power", argument)
+		}
+	}
+
+	x, y := operands[0], operands[1]
+
+	switch {
+	case x == 0 && y < 0:
+		return nil, fmt.Errorf("%w: zero raised to a negative power is undefined", ErrInvalidValue)
+	case x < 0 && y != math.Trunc(y):
+		return nil, fmt.Errorf("%w: a negative number raised to a non-integer power yields a complex result", ErrInvalidValue)
+	}
+
+	return math.Pow(x, y), nil
+}
+
+func momentOf(value TValue) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return parseTimestamp(v)
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %s is not a timestamp", ErrTypeMismatch, valueType(value))
+}
+
+func dateTrunc(arguments []TValue) (TValue, error) {
+	moment, err := momentOf(arguments[1])
+	if err != nil {
+		return nil, err
+	}
+
+	year, month, day := moment.Date()
+	hour, minute, second := moment.Clock()
+	location := moment.Location()
+
+	switch strings.ToLower(arguments[0].(string)) {
+	case "year":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, location), nil
+	case "quarter":
+		return time.Date(year, (month-1)/3*3+1, 1, 0, 0, 0, 0, location), nil
+	case "month":
+		return time.Date(year, month, 1, 0, 0, 0, 0, location), nil
+	case "week":
+		monday := day - (int(moment.Weekday())+6)%7
+		return time.Date(year, month, monday, 0, 0, 0, 0, location), nil
+	case "day":
+		return time.Date(year, month, day, 0, 0, 0, 0, location), nil
+	case "hour":
+		return time.Date(year, month, day, hour, 0, 0, 0, location), nil
+	case "minute":
+		return time.Date(year, month, day, hour, minute, 0, 0, location), nil
+	case "second":
+		return time.Date(year, month, day, hour, minute, second, 0, location), nil
+	}
+
+	return nil, fmt.Errorf("%w: unit %q not recognized", ErrInvalidValue, arguments[0])
+}
+
+func extract(arguments []TValue) (TValue, error) {
+	moment, err := momentOf(arguments[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var number *big.Rat
+
+	switch strings.ToLower(arguments[0].(string)) {
+	case "year":
+		number = big.NewRat(int64(moment.Year()), 1)
+	case "quarter":
+		number = big.NewRat(int64((moment.Month()-1)/3+1), 1)
+	case "month":
+		number = big.NewRat(int64(moment.Month()), 1)
+	case "week":
+		_, week := moment.ISOWeek()
+		number = big.NewRat(int64(week), 1)
+	case "day":
+		number = big.NewRat(int64(moment.Day()), 1)
+	case "hour":
+		number = big.NewRat(int64(moment.Hour()), 1)
+	case "minute":
+		number = big.NewRat(int64(moment.Minute()), 1)
+	case "second":
+		number = big.NewRat(int64(moment.Second())*1e9+int64(moment.Nanosecond()), 1e9)
+	case "dow":
+		number = big.NewRat(int64(moment.Weekday()), 1)
+	case "isodow":
+		number = big.NewRat(int64((int(moment.Weekday())+6)%7+1), 1)
+	case "doy":
+		number = big.NewRat(int64(moment.YearDay()), 1)
+	case "epoch":
+		number = big.NewRat(moment.UnixMicro(), 1e6)
+	default:
+		return nil, fmt.Errorf("%w: unit %q not recognized", ErrInvalidValue, arguments[0])
+	}
+
+	return numericOf(number), nil
+}
@@ -0,0 +1,467 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+// tJoinCondition matches rows of two relations either by an ON expression,
+// evaluated over the concatenated row, or by pairs of USING columns. The key
+// columns are the USING columns or the equi-join pairs found in ON.
+type tJoinCondition struct {
+	on        *ast.TExpression
+	columns   []*tRelationColumn
+	using     bool
+	leftKeys  []int
+	rightKeys []int
+	query     *tQuery
+}
+
+func (query *tQuery) fromRelation(expression *ast.TTableExpression) (*tRelation, error) {
+	if expression == nil {
+		return &tRelation{rows: [][]TValue{{}}}, nil
+	}
+
+	switch expression.Type {
+	case ast.TableReferenceType:
+		table, err := query.mb.Table(expression.Table.Table.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		relation := relationOf(table)
+
+		if expression.Table.Alias != nil {
+			relation = aliasRelation(relation, expression.Table.Alias.Value)
+		}
+
+		return relation, nil
+	case ast.JoinExpressionType:
+		left, err := query.fromRelation(expression.Join.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := query.fromRelation(expression.Join.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		return query.joinRelations(left, right, expression.Join)
+	case ast.DerivedTableType:
+		return query.derivedRelation(expression.Derived)
+	}
+
+	return nil, fmt.Errorf("Unsupported table expression")
+}
+
+func (query *tQuery) joinRelations(left *tRelation, right *tRelation, join *ast.TJoin) (*tRelation, error) {
+	condition, err := query.joinCondition(left, right, join)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := condition.candidates(left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	joined := &tRelation{columns: condition.output(left, right)}
+	rightMatched := make([]bool, len(right.rows))
+
+	for i, leftRow := range left.rows {
+		matched := false
+
+		for _, j := range candidates[i] {
+			ok, err := condition.matches(leftRow, right.rows[j])
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				continue
+			}
+
+			matched = true
+			rightMatched[j] = true
+			joined.rows = append(joined.rows, condition.combine(leftRow, right.rows[j]))
+		}
+
+		if !matched && (join.Type == ast.LeftJoin || join.Type == ast.FullJoin) {
+			joined.rows = append(joined.rows, condition.combine(leftRow, make([]TValue, len(right.columns))))
+		}
+	}
+
+	if join.Type == ast.RightJoin || join.Type == ast.FullJoin {
+		for j, rightRow := range right.rows {
+			if !rightMatched[j] {
+				joined.rows = append(joined.rows, condition.combine(make([]TValue, len(left.columns)), rightRow))
+			}
+		}
+	}
+
+	return joined, nil
+}
+
+func (query *tQuery) joinCondition(left *tRelation, right *tRelation, join *ast.TJoin) (*tJoinCondition, error) {
+	condition := &tJoinCondition{
+		on:      join.On,
+		columns: append(append([]*tRelationColumn{}, left.columns...), right.columns...),
+		using:   len(join.Using) > 0,
+		query:   query,
+	}
+
+	if join.On != nil {
+		if err := query.rejectAggregates(join.On, "JOIN conditions"); err != nil {
+			return nil, err
+		}
+
+		if err := checkCondition(join.On, &tRowContext{columns: condition.columns, query: query}); err != nil {
+			return nil, err
+		}
+
+		condition.equiKeys(join.On, len(left.columns))
+	}
+
+	for i, column := range join.Using {
+		for _, previous := range join.Using[:i] {
+			if previous.Value == column.Value {
+				return nil, fmt.Errorf("%w: %s specified more than once in USING", ErrDuplicateColumn, column.Value)
+			}
+		}
+
+		leftIdx, err := resolveColumn(left.columns, "", column.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		rightIdx, err := resolveColumn(right.columns, "", column.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		leftType, rightType := left.columns[leftIdx].typ, right.columns[rightIdx].typ
+		if !comparableTypes(leftType, rightType) {
+			return nil, fmt.Errorf("%w: cannot compare %s with %s", ErrTypeMismatch, leftType, rightType)
+		}
+
+		condition.leftKeys = append(condition.leftKeys, leftIdx)
+		condition.rightKeys = append(condition.rightKeys, rightIdx)
+	}
+
+	return condition, nil
+}
+
+// equiKeys collects the column pairs compared with = in the top-level AND
+// chain of an ON condition, one column from each side of the join.
+func (condition *tJoinCondition) equiKeys(on *ast.TExpression, leftWidth int) {
+	if on.Type != ast.BinaryType {
+		return
+	}
+
+	switch on.Binary.Operator.Value {
+	case string(lexer.AndToken):
+		condition.equiKeys(on.Binary.A, leftWidth)
+		condition.equiKeys(on.Binary.B, leftWidth)
+	case string(lexer.EqualToken):
+		a, aok := condition.columnIndex(on.Binary.A)
+		b, bok := condition.columnIndex(on.Binary.B)
+		if !aok || !bok {
+			return
+		}
+
+		if a >= leftWidth {
+			a, b = b, a
+		}
+
+		if a < leftWidth && b >= leftWidth {
+			condition.leftKeys = append(condition.leftKeys, a)
+			condition.rightKeys = append(condition.rightKeys, b-leftWidth)
+		}
+	}
+}
+
+func (condition *tJoinCondition) columnIndex(expression *ast.TExpression) (int, bool) {
+	table, name, ok := columnReference(expression)
+	if !ok {
+		return 0, false
+	}
+
+	idx, err := resolveColumn(condition.columns, table, name)
+	return idx, err == nil
+}
+
+// candidates lists, for every left row, the right rows that may satisfy the
+// condition. Equi-joins use a merge join when both inputs are already sorted
+// on the join keys and a hash join otherwise; anything else is a nested loop.
+func (condition *tJoinCondition) candidates(left *tRelation, right *tRelation) ([][]int, error) {
+	if len(condition.leftKeys) > 0 {
+		if sortedOn(left.rows, condition.leftKeys) && sortedOn(right.rows, condition.rightKeys) {
+			return condition.mergeCandidates(left, right)
+		}
+
+		if hashable := condition.hashableKeys(left, right); len(hashable) > 0 {
+			return condition.hashCandidates(left, right, hashable), nil
+		}
+	}
+
+	all := make([]int, len(right.rows))
+	for j := range all {
+		all[j] = j
+	}
+
+	candidates := make([][]int, len(left.rows))
+	for i := range candidates {
+		candidates[i] = all
+	}
+
+	return candidates, nil
+}
+
+// hashableKeys returns the key pairs whose values are equal exactly when
+// their hash keys are, which excludes e.g. TEXT compared with DATE.
+func (condition *tJoinCondition) hashableKeys(left *tRelation, right *tRelation) []int {
+	hashable := []int{}
+
+	for i, leftIdx := range condition.leftKeys {
+		a, b := left.columns[leftIdx].typ, right.columns[condition.rightKeys[i]].typ
+
+		switch {
+		case a.IsNumeric() && b.IsNumeric(),
+			isTextType(a) && isTextType(b),
+			isTimeType(a) && isTimeType(b),
+			a == b && a != UnknownType:
+			hashable = append(hashable, i)
+		}
+	}
+
+	return hashable
+}
+
+func (condition *tJoinCondition) hashCandidates(left *tRelation, right *tRelation, keys []int) [][]int {
+	table := map[string][]int{}
+
+	for j, rightRow := range right.rows {
+		if key, ok := hashKey(rightRow, condition.rightKeys, keys); ok {
+			table[key] = append(table[key], j)
+		}
+	}
+
+	candidates := make([][]int, len(left.rows))
+
+	for i, leftRow := range left.rows {
+		if key, ok := hashKey(leftRow, condition.leftKeys, keys); ok {
+			candidates[i] = table[key]
+		}
+	}
+
+	return candidates
+}
+
+// hashKey builds the hash table key of a row, normalizing numbers so that
+// INT, REAL and NUMERIC keys meet and -0 equals 0. Rows with a NULL key never
+// match. Distinct NUMERIC keys may share a hash key, so candidates are still
+// checked against the condition.
+func hashKey(row []TValue, columns []int, keys []int) (string, bool) {
+	values := make([]TValue, len(keys))
+
+	for i, key := range keys {
+		value := row[columns[key]]
+
+		switch number := value.(type) {
+		case nil:
+			return "", false
+		case int64:
+			value = float64(number)
+		case TNumeric:
+			value = number.float()
+		case time.Time:
+			value = number.UnixNano()
+		}
+
+		values[i] = value
+	}
+
+	return rowKey(values), true
+}
+
+func (condition *tJoinCondition) mergeCandidates(left *tRelation, right *tRelation) ([][]int, error) {
+	all := make([]int, len(right.rows))
+	for j := range all {
+		all[j] = j
+	}
+
+	candidates := make([][]int, len(left.rows))
+	i, j := 0, 0
+
+	for i < len(left.rows) && j < len(right.rows) {
+		cmp, err := compareKeys(left.rows[i], condition.leftKeys, right.rows[j], condition.rightKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp < 0 || hasNullKey(left.rows[i], condition.leftKeys) {
+			i++
+			continue
+		}
+
+		if cmp > 0 || hasNullKey(right.rows[j], condition.rightKeys) {
+			j++
+			continue
+		}
+
+		end := j + 1
+		for end < len(right.rows) {
+			cmp, err := compareKeys(right.rows[j], condition.rightKeys, right.rows[end], condition.rightKeys)
+			if err != nil {
+				return nil, err
+			}
+
+			if cmp != 0 {
+				break
+			}
+
+			end++
+		}
+
+		for ; i < len(left.rows); i++ {
+			cmp, err := compareKeys(left.rows[i], condition.leftKeys, right.rows[j], condition.rightKeys)
+			if err != nil {
+				return nil, err
+			}
+
+			if cmp != 0 {
+				break
+			}
+
+			candidates[i] = all[j:end]
+		}
+
+		j = end
+	}
+
+	return candidates, nil
+}
+
+// sortedOn reports whether rows are in ascending order of the key columns,
+// with NULLs last.
+func sortedOn(rows [][]TValue, keys []int) bool {
+	for i := 1; i < len(rows); i++ {
+		cmp, err := compareKeys(rows[i-1], keys, rows[i], keys)
+		if err != nil || cmp > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func compareKeys(a []TValue, aKeys []int, b []TValue, bKeys []int) (int, error) {
+	for i, aKey := range aKeys {
+		x, y := a[aKey], b[bKeys[i]]
+
+		switch {
+		case x == nil && y == nil:
+			continue
+		case x == nil:
+			return 1, nil
+		case y == nil:
+			return -1, nil
+		}
+
+		cmp, err := compareValues(x, y)
+		if err != nil || cmp != 0 {
+			return cmp, err
+		}
+	}
+
+	return 0, nil
+}
+
+func hasNullKey(row []TValue, keys []int) bool {
+	for _, key := range keys {
+		if row[key] == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// output lists the joined columns. USING columns come first as merged
+// columns, while the originals stay reachable only by qualified name.
+func (condition *tJoinCondition) output(left *tRelation, right *tRelation) []*tRelationColumn {
+	if !condition.using {
+		return condition.columns
+	}
+
+	columns := []*tRelationColumn{}
+
+	for i, leftIdx := range condition.leftKeys {
+		merged := *left.columns[leftIdx]
+		merged.table = ""
+
+		if merged.typ == UnknownType {
+			merged.typ = right.columns[condition.rightKeys[i]].typ
+		}
+
+		columns = append(columns, &merged)
+	}
+
+	hide := func(relationColumns []*tRelationColumn, keys []int) {
+		for i, column := range relationColumns {
+			hidden := *column
+			hidden.hidden = slices.Contains(keys, i)
+			columns = append(columns, &hidden)
+		}
+	}
+
+	hide(left.columns, condition.leftKeys)
+	hide(right.columns, condition.rightKeys)
+
+	return columns
+}
+
+func (condition *tJoinCondition) matches(leftRow []TValue, rightRow []TValue) (bool, error) {
+	if condition.on != nil {
+		row := append(append([]TValue{}, leftRow...), rightRow...)
+		return evaluateCondition(condition.on, &tRowContext{columns: condition.columns, row: row, query: condition.query})
+	}
+
+	for i, leftIdx := range condition.leftKeys {
+		a, b := leftRow[leftIdx], rightRow[condition.rightKeys[i]]
+		if a == nil || b == nil {
+			return false, nil
+		}
+
+		cmp, err := compareValues(a, b)
+		if err != nil || cmp != 0 {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (condition *tJoinCondition) combine(leftRow []TValue, rightRow []TValue) []TValue {
+	if !condition.using {
+		return append(append(make([]TValue, 0, len(leftRow)+len(rightRow)), leftRow...), rightRow...)
+	}
+
+	row := make([]TValue, 0, len(condition.leftKeys)+len(leftRow)+len(rightRow))
+
+	for i, leftIdx := range condition.leftKeys {
+		value := leftRow[leftIdx]
+		if value == nil {
+			value = rightRow[condition.rightKeys[i]]
+		}
+
+		row = append(row, value)
+	}
+
+	return append(append(row, leftRow...), rightRow...)
+}
@@ -0,0 +1,560 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+
+	"pkg/ast"
+)
+
+type TMemoryBackend struct {
+	tables     map[string]*TTable
+	functions  map[string]*tScalarFunction
+	aggregates map[string]*tAggregateFunction
+}
+
+func NewMemoryBackend() *TMemoryBackend {
+	return &TMemoryBackend{
+		tables:     map[string]*TTable{},
+		functions:  map[string]*tScalarFunction{},
+		aggregates: map[string]*tAggregateFunction{},
+	}
+}
+
+func (mb *TMemoryBackend) Tables() []*TTable {
+	tables := make([]*TTable, 0, len(mb.tables))
+	for _, table := range mb.tables {
+		tables = append(tables, table)
+	}
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+
+	return tables
+}
+
+func (mb *TMemoryBackend) Table(name string) (*TTable, error) {
+	table, ok := mb.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTableDoesNotExist, name)
+	}
+
+	return table, nil
+}
+
+func (mb *TMemoryBackend) Execute(statement *ast.TStatement) (*TResults, error) {
+	switch statement.Type {
+	case ast.CreateTableType:
+		return &TResults{}, mb.CreateTable(statement.CreateTable)
+	case ast.InsertType:
+		return affectedRows(mb.Insert(statement.Insert))
+	case ast.UpdateType:
+		return affectedRows(mb.Update(statement.Update))
+	case ast.DeleteType:
+		return affectedRows(mb.Delete(statement.Delete))
+	case ast.DropTableType:
+		return &TResults{}, mb.DropTable(statement.DropTable)
+	case ast.AlterTableType:
+		return &TResults{}, mb.AlterTable(statement.AlterTable)
+	case ast.SelectType:
+		return mb.Select(statement.Select)
+	}
+
+	return nil, ErrInvalidStatement
+}
+
+func (mb *TMemoryBackend) ExecuteTree(tree *ast.TSyntaxTree) ([]*TResults, error) {
+	results := []*TResults{}
+
+	for _, statement := range tree.Statements {
+		result, err := mb.Execute(statement)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (mb *TMemoryBackend) CreateTable(statement *ast.TCreateTableStatement) error {
+	tableName := statement.TableName.Value
+	if _, ok := mb.tables[tableName]; ok {
+		return fmt.Errorf("%w: %s", ErrTableAlreadyExists, tableName)
+	}
+
+	table := &TTable{Name: tableName}
+	query := mb.newQuery()
+
+	if statement.As != nil {
+		return mb.createTableAs(table, statement.As)
+	}
+
+	if statement.Columns == nil {
+		mb.tables[tableName] = table
+		return nil
+	}
+
+	for _, columnMeta := range *statement.Columns {
+		column, err := columnOf(columnMeta)
+		if err != nil {
+			return err
+		}
+
+		if table.Column(column.Name) != nil {
+			return fmt.Errorf("%w: %s in table %s", ErrDuplicateColumn, column.Name, tableName)
+		}
+
+		if _, err := columnDefault(column, query); err != nil {
+			return fmt.Errorf("%w for column %s.%s", err, tableName, column.Name)
+		}
+
+		table.Columns = append(table.Columns, column)
+	}
+
+	for _, columnMeta := range *statement.Columns {
+		for _, constraint := range columnConstraints(columnMeta) {
+			if err := table.addConstraint(constraint, query); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, constraintMeta := range statement.Constraints {
+		if err := table.addConstraint(tableConstraint(constraintMeta), query); err != nil {
+			return err
+		}
+	}
+
+	mb.tables[tableName] = table
+
+	return nil
+}
+
+func (mb *TMemoryBackend) createTableAs(table *TTable, statement *ast.TSelectStatement) error {
+	results, err := mb.Select(statement)
+	if err != nil {
+		return err
+	}
+
+	for _, resultColumn := range results.Columns {
+		if table.Column(resultColumn.Name) != nil {
+			return fmt.Errorf("%w: %s in table %s", ErrDuplicateColumn, resultColumn.Name, table.Name)
+		}
+
+		columnType := resultColumn.Type
+		if columnType == UnknownType {
+			columnType = TextType
+		}
+
+		table.Columns = append(table.Columns, &TColumn{Name: resultColumn.Name, Type: columnType})
+	}
+
+	table.Rows = results.Rows
+	mb.tables[table.Name] = table
+
+	return nil
+}
+
+func (mb *TMemoryBackend) DropTable(statement *ast.TDropTableStatement) error {
+	tableName := statement.TableName.Value
+	if _, ok := mb.tables[tableName]; !ok {
+		if statement.IfExists {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %s", ErrTableDoesNotExist, tableName)
+	}
+
+	delete(mb.tables, tableName)
+
+	return nil
+}
+
+func (mb *TMemoryBackend) AlterTable(statement *ast.TAlterTableStatement) error {
+	table, err := mb.Table(statement.TableName.Value)
+	if err != nil {
+		return err
+	}
+
+	switch statement.Action {
+	case ast.AddColumnAction:
+		return table.addColumn(statement.Column, mb.newQuery())
+	case ast.DropColumnAction:
+		return table.dropColumn(statement.Target.Value)
+	case ast.RenameColumnAction:
+		return table.renameColumn(statement.Target.Value, statement.NewName.Value)
+	case ast.RenameTableAction:
+		newName := statement.NewName.Value
+		if _, ok := mb.tables[newName]; ok {
+			return fmt.Errorf("%w: %s", ErrTableAlreadyExists, newName)
+		}
+
+		delete(mb.tables, table.Name)
+		table.Name = newName
+		mb.tables[newName] = table
+
+		return nil
+	}
+
+	return ErrInvalidStatement
+}
+
+func (mb *TMemoryBackend) Insert(statement *ast.TInsertStatement) (uint, error) {
+	table, err := mb.Table(statement.Table.Value)
+	if err != nil {
+		return 0, err
+	}
+
+	targets, err := table.insertTargets(statement.Columns)
+	if err != nil {
+		return 0, err
+	}
+
+	query := mb.newQuery()
+
+	source, err := query.insertSource(statement, table, targets)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := make([][]TValue, 0, len(source))
+
+	for _, values := range source {
+		if len(values) != len(targets) {
+			return 0, fmt.Errorf(
+				"%w: table %s expects %d values, got %d",
+				ErrMissingValues,
+				table.Name,
+				len(targets),
+				len(values),
+			)
+		}
+
+		row, err := table.defaultRow(query)
+		if err != nil {
+			return 0, err
+		}
+
+		for i, value := range values {
+			column := table.Columns[targets[i]]
+			row[targets[i]], err = column.coerce(value)
+			if err != nil {
+				return 0, fmt.Errorf("%w for column %s.%s", err, table.Name, column.Name)
+			}
+		}
+
+		if err := table.validateRow(row, query); err != nil {
+			return 0, err
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := table.indexRows(nil, rows); err != nil {
+		return 0, err
+	}
+
+	table.Rows = append(table.Rows, rows...)
+
+	return uint(len(source)), nil
+}
+
+// insertSource computes the inserted rows, checking that the type of each
+// value can be stored in its target column when the counts match.
+func (query *tQuery) insertSource(statement *ast.TInsertStatement, table *TTable, targets []int) ([][]TValue, error) {
+	if statement.Select != nil {
+		results, err := query.selectStatement(statement.Select)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results.Columns) == len(targets) {
+			for i, column := range results.Columns {
+				if err := table.checkAssignment(table.Columns[targets[i]], column.Type); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return results.Rows, nil
+	}
+
+	source := make([][]TValue, len(statement.Values))
+	ctx := &tRowContext{query: query}
+
+	for i, expressions := range statement.Values {
+		source[i] = make([]TValue, len(expressions))
+
+		for j, expression := range expressions {
+			if len(expressions) == len(targets) {
+				valueType, err := expressionType(expression, ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				if err := table.checkAssignment(table.Columns[targets[j]], valueType); err != nil {
+					return nil, err
+				}
+			}
+
+			value, err := evaluateExpression(expression, ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			source[i][j] = value
+		}
+	}
+
+	return source, nil
+}
+
+func (mb *TMemoryBackend) Update(statement *ast.TUpdateStatement) (uint, error) {
+	table, err := mb.Table(statement.Table.Value)
+	if err != nil {
+		return 0, err
+	}
+
+	relation := relationOf(table)
+	scope := &tRowContext{columns: relation.columns, query: mb.newQuery()}
+
+	if err := scope.query.rejectAggregates(statement.Where, "WHERE"); err != nil {
+		return 0, err
+	}
+
+	if err := checkCondition(statement.Where, scope); err != nil {
+		return 0, err
+	}
+
+	targets := make([]int, len(statement.Assignments))
+	for i, assignment := range statement.Assignments {
+		if err := scope.query.rejectAggregates(assignment.Value, "UPDATE"); err != nil {
+			return 0, err
+		}
+
+		targets[i], err = resolveColumn(relation.columns, "", assignment.Column.Value)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, target := range targets[:i] {
+			if target == targets[i] {
+				return 0, fmt.Errorf("%w: %s assigned more than once", ErrDuplicateColumn, assignment.Column.Value)
+			}
+		}
+
+		valueType, err := expressionType(assignment.Value, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := table.checkAssignment(table.Columns[targets[i]], valueType); err != nil {
+			return 0, err
+		}
+	}
+
+	updated := make([][]TValue, len(table.Rows))
+	removed, added := [][]TValue{}, [][]TValue{}
+
+	for i, row := range table.Rows {
+		updated[i] = row
+
+		ctx := &tRowContext{columns: relation.columns, row: row, query: scope.query}
+
+		matches, err := evaluateCondition(statement.Where, ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		if !matches {
+			continue
+		}
+
+		newRow := append([]TValue{}, row...)
+		for j, assignment := range statement.Assignments {
+			value, err := evaluateExpression(assignment.Value, ctx)
+			if err != nil {
+				return 0, err
+			}
+
+			column := table.Columns[targets[j]]
+			newRow[targets[j]], err = column.coerce(value)
+			if err != nil {
+				return 0, fmt.Errorf("%w for column %s.%s", err, table.Name, column.Name)
+			}
+		}
+
+		if err := table.validateRow(newRow, scope.query); err != nil {
+			return 0, err
+		}
+
+		updated[i] = newRow
+		removed = append(removed, row)
+		added = append(added, newRow)
+	}
+
+	if err := table.indexRows(removed, added); err != nil {
+		return 0, err
+	}
+
+	table.Rows = updated
+
+	return uint(len(added)), nil
+}
+
+func (mb *TMemoryBackend) Delete(statement *ast.TDeleteStatement) (uint, error) {
+	table, err := mb.Table(statement.Table.Value)
+	if err != nil {
+		return 0, err
+	}
+
+	relation := relationOf(table)
+	scope := &tRowContext{columns: relation.columns, query: mb.newQuery()}
+
+	if err := scope.query.rejectAggregates(statement.Where, "WHERE"); err != nil {
+		return 0, err
+	}
+
+	if err := checkCondition(statement.Where, scope); err != nil {
+		return 0, err
+	}
+
+	kept, removed := [][]TValue{}, [][]TValue{}
+
+	for _, row := range table.Rows {
+		matches, err := evaluateCondition(statement.Where, &tRowContext{columns: relation.columns, row: row, query: scope.query})
+		if err != nil {
+			return 0, err
+		}
+
+		if matches {
+			removed = append(removed, row)
+		} else {
+			kept = append(kept, row)
+		}
+	}
+
+	if err := table.indexRows(removed, nil); err != nil {
+		return 0, err
+	}
+
+	table.Rows = kept
+
+	return uint(len(removed)), nil
+}
+
+func (mb *TMemoryBackend) Select(statement *ast.TSelectStatement) (*TResults, error) {
+	return mb.newQuery().selectStatement(statement)
+}
+
+func (query *tQuery) selectStatement(statement *ast.TSelectStatement) (*TResults, error) {
+	if statement.Compound != nil {
+		return query.compoundStatement(statement)
+	}
+
+	source, err := query.fromRelation(statement.From)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := expandRules(statement.Rules, source.columns)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := *statement
+	expanded.Rules = rules
+	statement = &expanded
+
+	source, err = filterRelation(source, statement.Where, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &TResults{}
+	scope := &tRowContext{columns: source.columns, query: query}
+
+	for _, rule := range statement.Rules {
+		columnType, err := expressionType(rule.Expression, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		results.Columns = append(results.Columns, &TResultColumn{
+			Name: ruleName(rule),
+			Type: columnType,
+		})
+	}
+
+	keys, err := sortKeys(statement.OrderBy, results.Columns, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset, err := pagingBounds(statement, query)
+	if err != nil {
+		return nil, err
+	}
+
+	contexts := make([]*tRowContext, len(source.rows))
+
+	if query.isGrouped(statement, keys) {
+		contexts, err = groupRelation(source, statement, keys, query)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for i, sourceRow := range source.rows {
+			contexts[i] = &tRowContext{columns: source.columns, row: sourceRow, query: query}
+		}
+	}
+
+	sorter := newRowSorter(keys, limit)
+
+	for _, ctx := range contexts {
+		if len(keys) == 0 && limit >= 0 && sorter.Len() >= limit {
+			break
+		}
+
+		row := make([]TValue, len(statement.Rules))
+
+		for i, rule := range statement.Rules {
+			value, err := evaluateExpression(rule.Expression, ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			row[i] = value
+		}
+
+		keyValues := make([]TValue, len(keys))
+
+		for i, key := range keys {
+			if key.column >= 0 {
+				keyValues[i] = row[key.column]
+				continue
+			}
+
+			value, err := evaluateExpression(key.expression, ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			keyValues[i] = value
+		}
+
+		sorter.add(keyValues, row)
+	}
+
+	rows, err := sorter.sorted()
+	if err != nil {
+		return nil, err
+	}
+
+	results.Rows = rows[min(offset, len(rows)):]
+
+	return results, nil
+}
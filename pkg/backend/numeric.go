@@ -0,0 +1,190 @@
+package backend
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"pkg/lexer"
+)
+
+// numericDivisionScale is the number of fractional digits a NUMERIC quotient
+// is rounded to when it has no exact decimal representation, e.g. 1 / 3.
+const numericDivisionScale = 20
+
+// numericMaxExponent bounds the exponent of NUMERIC text, which is expanded
+// into exact digits.
+const numericMaxExponent = 1000
+
+var numericPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)(?:[eE]([+-]?\d+))?$`)
+
+var (
+	bigOne  = big.NewInt(1)
+	bigTwo  = big.NewInt(2)
+	bigFive = big.NewInt(5)
+	bigTen  = big.NewInt(10)
+)
+
+func parseNumeric(source string) (TNumeric, error) {
+	source = strings.TrimSpace(source)
+
+	match := numericPattern.FindStringSubmatch(source)
+	if match == nil {
+		return "", fmt.Errorf("%w: %q is not %s", ErrInvalidValue, source, NumericType)
+	}
+
+	if exponent, err := strconv.Atoi(match[2]); match[2] != "" && (err != nil || abs(exponent) > numericMaxExponent) {
+		return "", fmt.Errorf("%w: %s does not fit %s", ErrOutOfRange, source, NumericType)
+	}
+
+	number, ok := new(big.Rat).SetString(source)
+	if !ok {
+		return "", fmt.Errorf("%w: %q is not %s", ErrInvalidValue, source, NumericType)
+	}
+
+	return numericOf(number), nil
+}
+
+// numericOf formats a number as the canonical text of a TNumeric: the
+// shortest exact decimal, without an exponent or trailing zeros.
+func numericOf(number *big.Rat) TNumeric {
+	scale, exact := decimalScale(number)
+	if !exact {
+		number = roundRat(number, numericDivisionScale)
+		scale, _ = decimalScale(number)
+	}
+
+	return TNumeric(number.FloatString(scale))
+}
+
+func floatNumeric(number float64) (TNumeric, error) {
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return "", fmt.Errorf("%w: %s does not fit %s", ErrOutOfRange, formatText(number), NumericType)
+	}
+
+	return parseNumeric(strconv.FormatFloat(number, 'g', -1, 64))
+}
+
+func (number TNumeric) rat() *big.Rat {
+	value, _ := new(big.Rat).SetString(string(number))
+	return value
+}
+
+func (number TNumeric) float() float64 {
+	value, _ := strconv.ParseFloat(string(number), 64)
+	return value
+}
+
+// decimalScale returns the number of fractional digits needed to write the
+// number exactly, or false if its decimal expansion does not terminate.
+func decimalScale(number *big.Rat) (int, bool) {
+	denominator := new(big.Int).Set(number.Denom())
+	twos := removeFactor(denominator, bigTwo)
+	fives := removeFactor(denominator, bigFive)
+
+	return max(twos, fives), denominator.Cmp(bigOne) == 0
+}
+
+// removeFactor divides the factor out of the number as often as it goes and
+// returns how often that was.
+func removeFactor(number *big.Int, factor *big.Int) int {
+	quotient, remainder := new(big.Int), new(big.Int)
+	count := 0
+
+	for {
+		quotient.QuoRem(number, factor, remainder)
+		if remainder.Sign() != 0 {
+			return count
+		}
+
+		number.Set(quotient)
+		count++
+	}
+}
+
+// roundRat rounds the number to the given number of fractional digits, half
+// away from zero. A negative scale rounds to tens, hundreds and so on.
+func roundRat(number *big.Rat, scale int) *big.Rat {
+	if current, exact := decimalScale(number); exact && scale >= current {
+		return number
+	}
+
+	if scale < 0 {
+		integer := new(big.Int).Quo(number.Num(), number.Denom())
+		if -scale > len(integer.Text(10)) {
+			return new(big.Rat)
+		}
+	}
+
+	factor := new(big.Rat).SetInt(new(big.Int).Exp(bigTen, big.NewInt(int64(abs(scale))), nil))
+	scaled := new(big.Rat).Set(number)
+
+	if scale >= 0 {
+		scaled.Mul(scaled, factor)
+	} else {
+		scaled.Quo(scaled, factor)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), bigTwo).Cmp(scaled.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(scaled.Sign())))
+	}
+
+	rounded := new(big.Rat).SetInt(quotient)
+
+	if scale >= 0 {
+		return rounded.Quo(rounded, factor)
+	}
+
+	return rounded.Mul(rounded, factor)
+}
+
+// floorRat returns the largest integer not greater than the number.
+func floorRat(number *big.Rat) *big.Rat {
+	quotient := new(big.Int).Div(number.Num(), number.Denom())
+	return new(big.Rat).SetInt(quotient)
+}
+
+func abs(number int) int {
+	if number < 0 {
+		return -number
+	}
+
+	return number
+}
+
+func numericArithmetic(operator string, a *big.Rat, b *big.Rat) (TValue, error) {
+	result := new(big.Rat)
+
+	switch lexer.TSymbolToken(operator) {
+	case lexer.PlusToken:
+		return numericOf(result.Add(a, b)), nil
+	case lexer.MinusToken:
+		return numericOf(result.Sub(a, b)), nil
+	case lexer.AsteriksToken:
+		return numericOf(result.Mul(a, b)), nil
+	}
+
+	if b.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	result.Quo(a, b)
+
+	if lexer.TSymbolToken(operator) == lexer.PercentToken {
+		truncated := new(big.Rat).SetInt(new(big.Int).Quo(result.Num(), result.Denom()))
+		return numericOf(result.Sub(a, truncated.Mul(truncated, b))), nil
+	}
+
+	scale := numericDivisionScale
+	for _, operand := range []*big.Rat{a, b} {
+		if operandScale, _ := decimalScale(operand); operandScale > scale {
+			scale = operandScale
+		}
+	}
+
+	return numericOf(roundRat(result, scale)), nil
+}
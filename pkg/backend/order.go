@@ -0,0 +1,266 @@
+package backend
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+type tSortKey struct {
+	column     int
+	expression *ast.TExpression
+	descending bool
+	nullsFirst bool
+}
+
+type tSortedRow struct {
+	keys []TValue
+	row  []TValue
+	seq  int
+}
+
+// tRowSorter orders result rows by ORDER BY keys. With a limit it keeps only
+// the first limit rows in a max-heap instead of sorting the whole result.
+type tRowSorter struct {
+	keys  []*tSortKey
+	limit int
+	rows  []*tSortedRow
+	seen  int
+	err   error
+}
+
+func sortKeys(
+	orderBy []*ast.TOrderTerm,
+	results []*TResultColumn,
+	scope *tRowContext,
+) ([]*tSortKey, error) {
+	keys := []*tSortKey{}
+
+	for _, term := range orderBy {
+		key := &tSortKey{
+			column:     -1,
+			descending: term.Descending,
+			nullsFirst: term.Nulls == ast.NullsFirst || (term.Nulls == ast.DefaultNulls && term.Descending),
+		}
+
+		column, err := outputColumn(term.Expression, results)
+		if err != nil {
+			return nil, err
+		}
+
+		if column >= 0 {
+			key.column = column
+		} else {
+			if _, err := expressionType(term.Expression, scope); err != nil {
+				return nil, err
+			}
+
+			key.expression = term.Expression
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// outputColumn resolves ORDER BY terms that refer to the select list, either
+// by position (ORDER BY 2) or by output name. Anything else returns -1 and is
+// evaluated against the source row.
+func outputColumn(expression *ast.TExpression, results []*TResultColumn) (int, error) {
+	if expression.Type != ast.LiteralType {
+		return -1, nil
+	}
+
+	switch expression.Literal.Type {
+	case lexer.NumericType:
+		position, err := strconv.ParseInt(expression.Literal.Value, 10, 64)
+		if err != nil {
+			return -1, nil
+		}
+
+		if position < 1 || position > int64(len(results)) {
+			return 0, fmt.Errorf("%w: ORDER BY position %d is not in select list", ErrInvalidValue, position)
+		}
+
+		return int(position - 1), nil
+	case lexer.IdentifierType:
+		for i, column := range results {
+			if column.Name == expression.Literal.Value {
+				return i, nil
+			}
+		}
+	}
+
+	return -1, nil
+}
+
+func pagingBound(expression *ast.TExpression, clause string, query *tQuery) (int, error) {
+	if expression == nil {
+		return -1, nil
+	}
+
+	scope := &tRowContext{query: query}
+
+	boundType, err := expressionType(expression, scope)
+	if err != nil {
+		return 0, err
+	}
+
+	if !isIntegerType(boundType) && boundType != UnknownType {
+		return 0, fmt.Errorf("%w: argument of %s must be an integer, not %s", ErrTypeMismatch, clause, boundType)
+	}
+
+	value, err := evaluateExpression(expression, scope)
+	if err != nil {
+		return 0, err
+	}
+
+	if value == nil {
+		return -1, nil
+	}
+
+	bound, ok := value.(int64)
+	if !ok {
+		return 0, fmt.Errorf("%w: argument of %s must be an integer, not %s", ErrTypeMismatch, clause, valueType(value))
+	}
+
+	if bound < 0 {
+		return 0, fmt.Errorf("%w: %s must not be negative", ErrInvalidValue, clause)
+	}
+
+	return int(bound), nil
+}
+
+// pagingBounds returns the number of sorted rows to keep, -1 for all of
+// them, and the number of leading rows to skip.
+func pagingBounds(statement *ast.TSelectStatement, query *tQuery) (int, int, error) {
+	limit, err := pagingBound(statement.Limit, "LIMIT", query)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offset, err := pagingBound(statement.Offset, "OFFSET", query)
+	if err != nil {
+		return 0, 0, err
+	}
+	offset = max(offset, 0)
+
+	if limit >= 0 {
+		limit += offset
+	}
+
+	return limit, offset, nil
+}
+
+func newRowSorter(keys []*tSortKey, limit int) *tRowSorter {
+	return &tRowSorter{keys: keys, limit: limit}
+}
+
+func (sorter *tRowSorter) add(keys []TValue, row []TValue) {
+	sortedRow := &tSortedRow{keys: keys, row: row, seq: sorter.seen}
+	sorter.seen++
+
+	if sorter.limit < 0 {
+		sorter.rows = append(sorter.rows, sortedRow)
+		return
+	}
+
+	if sorter.limit == 0 {
+		return
+	}
+
+	if len(sorter.rows) < sorter.limit {
+		heap.Push(sorter, sortedRow)
+		return
+	}
+
+	if sorter.compare(sortedRow, sorter.rows[0]) < 0 {
+		sorter.rows[0] = sortedRow
+		heap.Fix(sorter, 0)
+	}
+}
+
+func (sorter *tRowSorter) sorted() ([][]TValue, error) {
+	sort.Slice(sorter.rows, func(i, j int) bool {
+		return sorter.compare(sorter.rows[i], sorter.rows[j]) < 0
+	})
+
+	if sorter.err != nil {
+		return nil, sorter.err
+	}
+
+	if len(sorter.rows) == 0 {
+		return nil, nil
+	}
+
+	rows := make([][]TValue, len(sorter.rows))
+	for i, sortedRow := range sorter.rows {
+		rows[i] = sortedRow.row
+	}
+
+	return rows, nil
+}
+
+func (sorter *tRowSorter) compare(a *tSortedRow, b *tSortedRow) int {
+	for i, key := range sorter.keys {
+		x, y := a.keys[i], b.keys[i]
+
+		switch {
+		case x == nil && y == nil:
+			continue
+		case x == nil:
+			if key.nullsFirst {
+				return -1
+			}
+			return 1
+		case y == nil:
+			if key.nullsFirst {
+				return 1
+			}
+			return -1
+		}
+
+		cmp, err := compareValues(x, y)
+		if err != nil && sorter.err == nil {
+			sorter.err = err
+		}
+
+		if key.descending {
+			cmp = -cmp
+		}
+
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return a.seq - b.seq
+}
+
+func (sorter *tRowSorter) Len() int {
+	return len(sorter.rows)
+}
+
+func (sorter *tRowSorter) Less(i, j int) bool {
+	return sorter.compare(sorter.rows[i], sorter.rows[j]) > 0
+}
+
+func (sorter *tRowSorter) Swap(i, j int) {
+	sorter.rows[i], sorter.rows[j] = sorter.rows[j], sorter.rows[i]
+}
+
+func (sorter *tRowSorter) Push(row any) {
+	sorter.rows = append(sorter.rows, row.(*tSortedRow))
+}
+
+func (sorter *tRowSorter) Pop() any {
+	last := sorter.rows[len(sorter.rows)-1]
+	sorter.rows = sorter.rows[:len(sorter.rows)-1]
+
+	return last
+}
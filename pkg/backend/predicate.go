@@ -0,0 +1,431 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+// tLikeToken is a literal rune of a LIKE pattern or, if wildcard is set, one
+// of the wildcards % and _.
+type tLikeToken struct {
+	r        rune
+	wildcard bool
+}
+
+func inType(expression *ast.TInExpression, scope *tRowContext) (EColumnType, error) {
+	a, err := expressionType(expression.A, scope)
+	if err != nil {
+		return 0, err
+	}
+
+	candidates := []EColumnType{}
+
+	if expression.Subquery != nil {
+		b, err := subqueryType(&ast.TExpression{Subquery: expression.Subquery, Type: ast.SubqueryType}, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		candidates = append(candidates, b)
+	}
+
+	for _, value := range expression.List {
+		b, err := expressionType(value, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		candidates = append(candidates, b)
+	}
+
+	for _, b := range candidates {
+		if !comparableTypes(a, b) {
+			return 0, fmt.Errorf("%w: cannot compare %s with %s", ErrTypeMismatch, a, b)
+		}
+	}
+
+	return BoolType, nil
+}
+
+func betweenType(expression *ast.TBetweenExpression, scope *tRowContext) (EColumnType, error) {
+	a, err := expressionType(expression.A, scope)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, bound := range []*ast.TExpression{expression.Low, expression.High} {
+		b, err := expressionType(bound, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		if !comparableTypes(a, b) {
+			return 0, fmt.Errorf("%w: cannot compare %s with %s", ErrTypeMismatch, a, b)
+		}
+	}
+
+	return BoolType, nil
+}
+
+func likeType(expression *ast.TLikeExpression, scope *tRowContext) (EColumnType, error) {
+	operands := []*ast.TExpression{expression.A, expression.Pattern}
+	if expression.Escape != nil {
+		operands = append(operands, expression.Escape)
+	}
+
+	for _, operand := range operands {
+		operandType, err := expressionType(operand, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		if !isTextType(operandType) && operandType != UnknownType {
+			return 0, fmt.Errorf("%w: operator %s is not defined for %s", ErrTypeMismatch, likeOperator(expression), operandType)
+		}
+	}
+
+	return BoolType, nil
+}
+
+func caseType(expression *ast.TCaseExpression, scope *tRowContext) (EColumnType, error) {
+	var operandType EColumnType
+
+	if expression.Operand != nil {
+		var err error
+
+		operandType, err = expressionType(expression.Operand, scope)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	results := []*ast.TExpression{}
+
+	for _, when := range expression.Whens {
+		if expression.Operand == nil {
+			if err := checkCondition(when.Condition, scope); err != nil {
+				return 0, err
+			}
+		} else {
+			conditionType, err := expressionType(when.Condition, scope)
+			if err != nil {
+				return 0, err
+			}
+
+			if !comparableTypes(operandType, conditionType) {
+				return 0, fmt.Errorf("%w: cannot compare %s with %s", ErrTypeMismatch, operandType, conditionType)
+			}
+		}
+
+		results = append(results, when.Result)
+	}
+
+	if expression.Else != nil {
+		results = append(results, expression.Else)
+	}
+
+	resultType := UnknownType
+
+	for _, result := range results {
+		branchType, err := expressionType(result, scope)
+		if err != nil {
+			return 0, err
+		}
+
+		common, ok := commonType(resultType, branchType)
+		if !ok {
+			return 0, fmt.Errorf("%w: CASE types %s and %s cannot be matched", ErrTypeMismatch, resultType, branchType)
+		}
+
+		resultType = common
+	}
+
+	return resultType, nil
+}
+
+// evaluateIn follows SQL semantics: without a match the result is NULL if
+// the operand or any of the candidates is NULL.
+func evaluateIn(expression *ast.TInExpression, ctx *tRowContext) (TValue, error) {
+	a, err := evaluateExpression(expression.A, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := inCandidates(expression, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candidates) == 0 {
+		return expression.Not, nil
+	}
+
+	if a == nil {
+		return nil, nil
+	}
+
+	unknown := false
+
+	for _, candidate := range candidates {
+		if candidate == nil {
+			unknown = true
+			continue
+		}
+
+		cmp, err := compareValues(a, candidate)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp == 0 {
+			return !expression.Not, nil
+		}
+	}
+
+	if unknown {
+		return nil, nil
+	}
+
+	return expression.Not, nil
+}
+
+func inCandidates(expression *ast.TInExpression, ctx *tRowContext) ([]TValue, error) {
+	if expression.Subquery != nil {
+		rows, err := subqueryRows(expression.Subquery, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates := make([]TValue, len(rows))
+		for i, row := range rows {
+			candidates[i] = row[0]
+		}
+
+		return candidates, nil
+	}
+
+	candidates := make([]TValue, len(expression.List))
+
+	for i, value := range expression.List {
+		candidate, err := evaluateExpression(value, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates[i] = candidate
+	}
+
+	return candidates, nil
+}
+
+func evaluateBetween(expression *ast.TBetweenExpression, ctx *tRowContext) (TValue, error) {
+	a, err := evaluateExpression(expression.A, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	low, err := evaluateExpression(expression.Low, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	high, err := evaluateExpression(expression.High, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aboveLow, err := compareBound(a, low, string(lexer.GreaterEqualToken))
+	if err != nil {
+		return nil, err
+	}
+
+	belowHigh, err := compareBound(a, high, string(lexer.LessEqualToken))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := applyLogical(string(lexer.AndToken), aboveLow, belowHigh)
+	if err != nil || value == nil {
+		return value, err
+	}
+
+	return value.(bool) != expression.Not, nil
+}
+
+func compareBound(a TValue, bound TValue, operator string) (TValue, error) {
+	if a == nil || bound == nil {
+		return nil, nil
+	}
+
+	cmp, err := compareValues(a, bound)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyComparison(operator, cmp), nil
+}
+
+func evaluateLike(expression *ast.TLikeExpression, ctx *tRowContext) (TValue, error) {
+	a, err := evaluateExpression(expression.A, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := evaluateExpression(expression.Pattern, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var escape TValue = `\`
+
+	if expression.Escape != nil {
+		escape, err = evaluateExpression(expression.Escape, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if a == nil || pattern == nil || escape == nil {
+		return nil, nil
+	}
+
+	text, okA := a.(string)
+	patternText, okPattern := pattern.(string)
+	escapeText, okEscape := escape.(string)
+
+	if !okA || !okPattern || !okEscape {
+		return nil, fmt.Errorf(
+			"%w: operator %s is not defined for %s and %s",
+			ErrTypeMismatch,
+			likeOperator(expression),
+			valueType(a),
+			valueType(pattern),
+		)
+	}
+
+	if expression.Insensitive {
+		text, patternText = strings.ToLower(text), strings.ToLower(patternText)
+	}
+
+	tokens, err := likeTokens(patternText, escapeText)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchLike([]rune(text), tokens) != expression.Not, nil
+}
+
+func likeOperator(expression *ast.TLikeExpression) string {
+	if expression.Insensitive {
+		return strings.ToUpper(string(lexer.ILikeToken))
+	}
+
+	return strings.ToUpper(string(lexer.LikeToken))
+}
+
+// likeTokens splits a LIKE pattern into literal runes and wildcards. An empty
+// escape string disables escaping.
+func likeTokens(pattern string, escape string) ([]tLikeToken, error) {
+	if utf8.RuneCountInString(escape) > 1 {
+		return nil, fmt.Errorf("%w: invalid escape string %q", ErrInvalidValue, escape)
+	}
+
+	escapeRune, _ := utf8.DecodeRuneInString(escape)
+	runes := []rune(pattern)
+	tokens := []tLikeToken{}
+
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case escape != "" && runes[i] == escapeRune:
+			i++
+			if i == len(runes) {
+				return nil, fmt.Errorf("%w: LIKE pattern must not end with escape character", ErrInvalidValue)
+			}
+
+			tokens = append(tokens, tLikeToken{r: runes[i]})
+		case runes[i] == '%' || runes[i] == '_':
+			tokens = append(tokens, tLikeToken{r: runes[i], wildcard: true})
+		default:
+			tokens = append(tokens, tLikeToken{r: runes[i]})
+		}
+	}
+
+	return tokens, nil
+}
+
+// matchLike matches greedily and, on a mismatch, backtracks to the last %
+// letting it absorb one more rune.
+func matchLike(text []rune, pattern []tLikeToken) bool {
+	t, p := 0, 0
+	star, mark := -1, 0
+
+	for t < len(text) {
+		switch {
+		case p < len(pattern) && pattern[p].wildcard && pattern[p].r == '%':
+			star, mark = p, t
+			p++
+		case p < len(pattern) && (pattern[p].wildcard || pattern[p].r == text[t]):
+			t++
+			p++
+		case star >= 0:
+			mark++
+			t, p = mark, star+1
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p].wildcard && pattern[p].r == '%' {
+		p++
+	}
+
+	return p == len(pattern)
+}
+
+func evaluateCase(expression *ast.TCaseExpression, ctx *tRowContext) (TValue, error) {
+	var operand TValue
+
+	if expression.Operand != nil {
+		var err error
+
+		operand, err = evaluateExpression(expression.Operand, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, when := range expression.Whens {
+		matches, err := caseMatches(expression, when, operand, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			return evaluateExpression(when.Result, ctx)
+		}
+	}
+
+	if expression.Else == nil {
+		return nil, nil
+	}
+
+	return evaluateExpression(expression.Else, ctx)
+}
+
+func caseMatches(expression *ast.TCaseExpression, when *ast.TWhenClause, operand TValue, ctx *tRowContext) (bool, error) {
+	if expression.Operand == nil {
+		return evaluateCondition(when.Condition, ctx)
+	}
+
+	value, err := evaluateExpression(when.Condition, ctx)
+	if err != nil || operand == nil || value == nil {
+		return false, err
+	}
+
+	cmp, err := compareValues(operand, value)
+	return cmp == 0, err
+}
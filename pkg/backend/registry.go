@@ -0,0 +1,231 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+// RegisterFunction makes a Go function callable from SQL under the given
+// name in this backend. Like statements, registrations must not run
+// concurrently with other calls on the same backend.
+func (mb *TMemoryBackend) RegisterFunction(name string, function *TFunction) error {
+	name, err := mb.registrationName(name)
+	if err != nil {
+		return err
+	}
+
+	if function == nil || function.Evaluate == nil {
+		return fmt.Errorf("%w: %s has no implementation", ErrInvalidStatement, name)
+	}
+
+	if function.Variadic && len(function.Arguments) == 0 {
+		return fmt.Errorf("%w: variadic function %s declares no arguments", ErrInvalidStatement, name)
+	}
+
+	if function.Result == UnknownType {
+		return fmt.Errorf("%w: function %s declares no result type", ErrInvalidStatement, name)
+	}
+
+	arguments := append([]EColumnType{}, function.Arguments...)
+	scalar := &tScalarFunction{
+		minArguments: len(arguments),
+		maxArguments: len(arguments),
+		callOnNull:   function.CallOnNull,
+		volatile:     !function.Deterministic,
+		resultType: func(actual []EColumnType) (EColumnType, bool) {
+			for i, argument := range actual {
+				if !assignableType(argument, declaredArgument(arguments, i)) {
+					return 0, false
+				}
+			}
+
+			return function.Result, true
+		},
+		evaluate: func(values []TValue) (TValue, error) {
+			coerced := make([]TValue, len(values))
+
+			for i, value := range values {
+				value, err := coerceDeclared(value, declaredArgument(arguments, i))
+				if err != nil {
+					return nil, err
+				}
+
+				coerced[i] = value
+			}
+
+			result, err := function.Evaluate(coerced)
+			if err != nil {
+				return nil, err
+			}
+
+			return coerceDeclared(result, function.Result)
+		},
+	}
+
+	if function.Variadic {
+		scalar.maxArguments = -1
+	}
+
+	mb.functions[name] = scalar
+
+	return nil
+}
+
+// RegisterAggregate makes a Go aggregate function callable from SQL under the
+// given name, with the same restrictions as RegisterFunction.
+func (mb *TMemoryBackend) RegisterAggregate(name string, aggregate *TAggregate) error {
+	name, err := mb.registrationName(name)
+	if err != nil {
+		return err
+	}
+
+	if aggregate == nil || aggregate.New == nil {
+		return fmt.Errorf("%w: %s has no implementation", ErrInvalidStatement, name)
+	}
+
+	if aggregate.Result == UnknownType {
+		return fmt.Errorf("%w: aggregate %s declares no result type", ErrInvalidStatement, name)
+	}
+
+	mb.aggregates[name] = &tAggregateFunction{
+		callOnNull: aggregate.CallOnNull,
+		resultType: func(argument EColumnType) (EColumnType, bool) {
+			return aggregate.Result, assignableType(argument, aggregate.Argument)
+		},
+		newAccumulator: func() tAccumulator {
+			return &tRegisteredAccumulator{aggregate: aggregate, accumulator: aggregate.New()}
+		},
+	}
+
+	return nil
+}
+
+type tRegisteredAccumulator struct {
+	aggregate   *TAggregate
+	accumulator TAccumulator
+}
+
+func (accumulator *tRegisteredAccumulator) add(value TValue) error {
+	value, err := coerceDeclared(value, accumulator.aggregate.Argument)
+	if err != nil {
+		return err
+	}
+
+	return accumulator.accumulator.Add(value)
+}
+
+func (accumulator *tRegisteredAccumulator) result() (TValue, error) {
+	value, err := accumulator.accumulator.Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return coerceDeclared(value, accumulator.aggregate.Result)
+}
+
+func (mb *TMemoryBackend) registrationName(name string) (string, error) {
+	name = strings.ToLower(name)
+
+	if name == "" {
+		return "", fmt.Errorf("%w: function name is empty", ErrInvalidStatement)
+	}
+
+	// Only a plain identifier parses as a function call, so a keyword such
+	// as double could never be called.
+	tokens, err := lexer.Tokenize(name)
+	if err != nil || len(tokens) != 1 || tokens[0].Type != lexer.IdentifierType || tokens[0].Value != name {
+		return "", fmt.Errorf("%w: %q is not a valid function name", ErrInvalidStatement, name)
+	}
+
+	_, scalar := mb.scalarFunction(name)
+	_, aggregate := mb.aggregateFunction(name)
+
+	if scalar || aggregate {
+		return "", fmt.Errorf("%w: %s", ErrFunctionAlreadyExists, name)
+	}
+
+	return name, nil
+}
+
+// scalarFunction looks a scalar function up among the built-in functions and
+// those registered with the backend.
+func (mb *TMemoryBackend) scalarFunction(name string) (*tScalarFunction, bool) {
+	if function, ok := scalarFunctions[name]; ok {
+		return function, true
+	}
+
+	function, ok := mb.functions[name]
+
+	return function, ok
+}
+
+func (mb *TMemoryBackend) aggregateFunction(name string) (*tAggregateFunction, bool) {
+	if aggregate, ok := aggregateFunctions[name]; ok {
+		return aggregate, true
+	}
+
+	aggregate, ok := mb.aggregates[name]
+
+	return aggregate, ok
+}
+
+func declaredArgument(arguments []EColumnType, idx int) EColumnType {
+	return arguments[min(idx, len(arguments)-1)]
+}
+
+// assignableType reports whether a value of the actual type can be passed
+// where the declared type is expected without losing information.
+func assignableType(actual EColumnType, declared EColumnType) bool {
+	switch {
+	case declared == UnknownType || actual == UnknownType || actual == declared:
+		return true
+	case isIntegerType(actual) && isIntegerType(declared):
+		return widerInteger(actual, declared) == declared
+	case isIntegerType(actual) && declared.IsNumeric():
+		return true
+	case actual.IsNumeric() && (isFloatType(declared) || declared == NumericType):
+		return true
+	case isTextType(actual) && isTextType(declared):
+		return true
+	case actual == DateType && declared == TimestampType:
+		return true
+	}
+
+	return false
+}
+
+func coerceDeclared(value TValue, declared EColumnType) (TValue, error) {
+	if declared == UnknownType {
+		return value, nil
+	}
+
+	return coerceValue(value, declared)
+}
+
+func (query *tQuery) isVolatile(expression *ast.TExpression) bool {
+	if expression.Type != ast.FunctionType {
+		return false
+	}
+
+	function, ok := query.mb.scalarFunction(expression.Function.Name.Value)
+
+	return ok && function.volatile
+}
+
+func (query *tQuery) rejectVolatile(expression *ast.TExpression, clause string) error {
+	volatile := false
+
+	expression.Walk(func(node *ast.TExpression) bool {
+		volatile = volatile || query.isVolatile(node)
+		return !volatile
+	})
+
+	if volatile {
+		return fmt.Errorf("%w: non-deterministic functions are not allowed in %s", ErrInvalidConstraint, clause)
+	}
+
+	return nil
+}
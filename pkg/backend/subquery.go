@@ -0,0 +1,218 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"pkg/ast"
+)
+
+// tQuery carries what a statement needs to run nested SELECTs: the backend,
+// the row of the enclosing query for correlated subqueries and the subqueries
+// and arithmetic expressions typed so far, shared by every query nested in the
+// same statement. started is the time the statement began, which now()
+// returns for every call within it.
+type tQuery struct {
+	mb         *TMemoryBackend
+	outer      *tRowContext
+	subqueries map[*ast.TSelectStatement]*tSubquery
+	types      map[*ast.TBinaryExpression]EColumnType
+	started    time.Time
+}
+
+type tSubquery struct {
+	columns    []*TResultColumn
+	correlated bool
+	results    *TResults
+}
+
+func (mb *TMemoryBackend) newQuery() *tQuery {
+	return &tQuery{
+		mb:         mb,
+		subqueries: map[*ast.TSelectStatement]*tSubquery{},
+		types:      map[*ast.TBinaryExpression]EColumnType{},
+		started:    time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// schema returns a view of the backend with the same tables but no rows, in
+// which subqueries are run to learn their result columns.
+func (mb *TMemoryBackend) schema() *TMemoryBackend {
+	schema := &TMemoryBackend{tables: map[string]*TTable{}, functions: mb.functions, aggregates: mb.aggregates}
+
+	for name, table := range mb.tables {
+		empty := *table
+		empty.Rows = nil
+		empty.indexes = nil
+		schema.tables[name] = &empty
+	}
+
+	return schema
+}
+
+func (query *tQuery) nested(outer *tRowContext) *tQuery {
+	return &tQuery{
+		mb:         query.mb,
+		outer:      outer,
+		subqueries: query.subqueries,
+		types:      query.types,
+		started:    query.started,
+	}
+}
+
+// lookup resolves a column reference against the row's own columns and then
+// against the rows of the enclosing queries, innermost first.
+func (ctx *tRowContext) lookup(table string, name string) (*tRowContext, int, error) {
+	idx, err := resolveColumn(ctx.columns, table, name)
+
+	if errors.Is(err, ErrColumnDoesNotExist) && ctx.query != nil && ctx.query.outer != nil {
+		if outer, outerIdx, outerErr := ctx.query.outer.lookup(table, name); outerErr == nil {
+			return outer, outerIdx, nil
+		}
+	}
+
+	return ctx, idx, err
+}
+
+// subquery types a nested SELECT. A subquery that only resolves with the
+// columns of the enclosing scope is correlated and has to run once per row.
+func (query *tQuery) subquery(statement *ast.TSelectStatement, scope *tRowContext) (*tSubquery, error) {
+	if subquery, ok := query.subqueries[statement]; ok {
+		return subquery, nil
+	}
+
+	schema := query.mb.schema().newQuery()
+	subquery := &tSubquery{}
+
+	results, err := schema.selectStatement(statement)
+	if errors.Is(err, ErrColumnDoesNotExist) {
+		outer := &tRowContext{columns: scope.columns, row: make([]TValue, len(scope.columns)), query: scope.query}
+		subquery.correlated = true
+
+		results, err = schema.nested(outer).selectStatement(statement)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	subquery.columns = results.Columns
+	query.subqueries[statement] = subquery
+
+	return subquery, nil
+}
+
+// derivedRelation runs a subquery in FROM. It sees the enclosing queries of
+// the statement but not the other tables of the same FROM clause.
+func (query *tQuery) derivedRelation(derived *ast.TDerivedTable) (*tRelation, error) {
+	results, err := query.selectStatement(derived.Select)
+	if err != nil {
+		return nil, err
+	}
+
+	relation := resultRelation(results)
+
+	if derived.Alias != nil {
+		relation = aliasRelation(relation, derived.Alias.Value)
+	}
+
+	return relation, nil
+}
+
+func resultRelation(results *TResults) *tRelation {
+	relation := &tRelation{rows: results.Rows}
+
+	for _, column := range results.Columns {
+		relation.columns = append(relation.columns, &tRelationColumn{name: column.Name, typ: column.Type})
+	}
+
+	return relation
+}
+
+func rejectSubqueries(expression *ast.TExpression, clause string) error {
+	found := false
+
+	expression.Walk(func(node *ast.TExpression) bool {
+		switch node.Type {
+		case ast.SubqueryType, ast.ExistsType:
+			found = true
+		case ast.InType:
+			found = found || node.In.Subquery != nil
+		}
+
+		return !found
+	})
+
+	if found {
+		return fmt.Errorf("%w: subqueries are not allowed in %s", ErrInvalidSubquery, clause)
+	}
+
+	return nil
+}
+
+func subqueryType(expression *ast.TExpression, scope *tRowContext) (EColumnType, error) {
+	subquery, err := scope.query.subquery(expression.Subquery, scope)
+	if err != nil {
+		return 0, err
+	}
+
+	if expression.Type == ast.ExistsType {
+		return BoolType, nil
+	}
+
+	if len(subquery.columns) != 1 {
+		return 0, fmt.Errorf("%w: subquery must return only one column", ErrInvalidSubquery)
+	}
+
+	return subquery.columns[0].Type, nil
+}
+
+// subqueryRows runs a nested SELECT for the current row. Results of
+// uncorrelated subqueries are computed once per statement.
+func subqueryRows(statement *ast.TSelectStatement, ctx *tRowContext) ([][]TValue, error) {
+	subquery, err := ctx.query.subquery(statement, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if subquery.results != nil {
+		return subquery.results.Rows, nil
+	}
+
+	outer := ctx
+	if !subquery.correlated {
+		outer = nil
+	}
+
+	results, err := ctx.query.nested(outer).selectStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	if !subquery.correlated {
+		subquery.results = results
+	}
+
+	return results.Rows, nil
+}
+
+func evaluateSubquery(expression *ast.TExpression, ctx *tRowContext) (TValue, error) {
+	rows, err := subqueryRows(expression.Subquery, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if expression.Type == ast.ExistsType {
+		return len(rows) > 0, nil
+	}
+
+	switch len(rows) {
+	case 0:
+		return nil, nil
+	case 1:
+		return rows[0][0], nil
+	}
+
+	return nil, fmt.Errorf("%w: more than one row returned by a subquery used as an expression", ErrInvalidSubquery)
+}
@@ -0,0 +1,141 @@
+package backend
+
+import (
+	"errors"
+
+	"pkg/ast"
+)
+
+type EColumnType uint
+
+const (
+	IntType EColumnType = iota
+	TextType
+	BoolType
+	SmallIntType
+	BigIntType
+	RealType
+	DoubleType
+	NumericType
+	VarcharType
+	DateType
+	TimestampType
+	BlobType
+	UnknownType
+)
+
+var (
+	ErrTableDoesNotExist     = errors.New("Table does not exist")
+	ErrTableAlreadyExists    = errors.New("Table already exists")
+	ErrColumnDoesNotExist    = errors.New("Column does not exist")
+	ErrAmbiguousColumn       = errors.New("Ambiguous column reference")
+	ErrInvalidDatatype       = errors.New("Invalid datatype")
+	ErrMissingValues         = errors.New("Missing values")
+	ErrDuplicateColumn       = errors.New("Duplicate column")
+	ErrInvalidValue          = errors.New("Invalid value")
+	ErrInvalidStatement      = errors.New("Invalid statement")
+	ErrTypeMismatch          = errors.New("Type mismatch")
+	ErrDivisionByZero        = errors.New("Division by zero")
+	ErrOutOfRange            = errors.New("Value out of range")
+	ErrConstraintViolation   = errors.New("Constraint violation")
+	ErrInvalidConstraint     = errors.New("Invalid constraint")
+	ErrFunctionDoesNotExist  = errors.New("Function does not exist")
+	ErrFunctionAlreadyExists = errors.New("Function already exists")
+	ErrInvalidGrouping       = errors.New("Invalid grouping")
+	ErrInvalidSubquery       = errors.New("Invalid subquery")
+)
+
+// TValue is a single cell value: nil for NULL, int64 for integer types,
+// float64 for REAL and DOUBLE PRECISION, TNumeric for NUMERIC, string for
+// TEXT and VARCHAR, bool for BOOLEAN, time.Time for DATE and TIMESTAMP and
+// []byte for BLOB.
+type TValue interface{}
+
+// TNumeric is an exact decimal number in its canonical text form, e.g.
+// "-12.5": no exponent, no leading zeros and no trailing fractional zeros, so
+// that equal numbers are equal strings.
+type TNumeric string
+
+type TColumn struct {
+	Name      string
+	Type      EColumnType
+	Length    uint
+	Precision uint
+	Scale     uint
+	Default   *ast.TExpression
+	NotNull   bool
+}
+
+type TConstraint struct {
+	Type    ast.EConstraintType
+	Columns []string
+	Check   *ast.TExpression
+}
+
+type TTable struct {
+	Name        string
+	Columns     []*TColumn
+	Constraints []*TConstraint
+	Rows        [][]TValue
+
+	indexes map[*TConstraint]map[string]bool
+}
+
+// TFunction declares a scalar function implemented in Go. Arguments are
+// converted to the declared types before Evaluate is called, UnknownType
+// accepting any type, and with Variadic set the last type repeats. Result
+// must be a concrete type. Unless CallOnNull is set a NULL argument yields
+// NULL without calling Evaluate.
+type TFunction struct {
+	Arguments     []EColumnType
+	Variadic      bool
+	Result        EColumnType
+	Deterministic bool
+	CallOnNull    bool
+	Evaluate      func(arguments []TValue) (TValue, error)
+}
+
+// TAggregate declares an aggregate function implemented in Go. New returns
+// the accumulator of one group; NULL inputs are skipped unless CallOnNull is
+// set. As for TFunction, Result must be a concrete type.
+type TAggregate struct {
+	Argument   EColumnType
+	Result     EColumnType
+	CallOnNull bool
+	New        func() TAccumulator
+}
+
+type TAccumulator interface {
+	Add(value TValue) error
+	Result() (TValue, error)
+}
+
+type TResultColumn struct {
+	Name string
+	Type EColumnType
+}
+
+type TResults struct {
+	Columns      []*TResultColumn
+	Rows         [][]TValue
+	RowsAffected uint
+}
+
+type tRelationColumn struct {
+	table  string
+	name   string
+	typ    EColumnType
+	hidden bool
+}
+
+type tRelation struct {
+	columns []*tRelationColumn
+	rows    [][]TValue
+}
+
+type tRowContext struct {
+	columns    []*tRelationColumn
+	row        []TValue
+	aggregates map[*ast.TFunctionCall]TValue
+	query      *tQuery
+}
@@ -0,0 +1,398 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+
+	"pkg/ast"
+	"pkg/lexer"
+)
+
+func (table *TTable) Column(name string) *TColumn {
+	for _, column := range table.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+
+	return nil
+}
+
+func affectedRows(affected uint, err error) (*TResults, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return &TResults{RowsAffected: affected}, nil
+}
+
+func (table *TTable) columnIndex(name string) (int, error) {
+	for i, column := range table.Columns {
+		if column.Name == name {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("%w: %s.%s", ErrColumnDoesNotExist, table.Name, name)
+}
+
+func (table *TTable) addColumn(columnMeta *ast.TColumnMeta, query *tQuery) error {
+	column, err := columnOf(columnMeta)
+	if err != nil {
+		return err
+	}
+
+	if table.Column(column.Name) != nil {
+		return fmt.Errorf("%w: %s in table %s", ErrDuplicateColumn, column.Name, table.Name)
+	}
+
+	value, err := columnDefault(column, query)
+	if err != nil {
+		return fmt.Errorf("%w for column %s.%s", err, table.Name, column.Name)
+	}
+
+	rows := make([][]TValue, len(table.Rows))
+	for i, row := range table.Rows {
+		rows[i] = append(append([]TValue{}, row...), value)
+	}
+
+	candidate := &TTable{
+		Name:        table.Name,
+		Columns:     append(append([]*TColumn{}, table.Columns...), column),
+		Constraints: append([]*TConstraint{}, table.Constraints...),
+		Rows:        rows,
+	}
+
+	for _, constraint := range columnConstraints(columnMeta) {
+		if err := candidate.addConstraint(constraint, query); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		if err := candidate.validateRow(row, query); err != nil {
+			return err
+		}
+	}
+
+	if err := candidate.validateUnique(); err != nil {
+		return err
+	}
+
+	*table = *candidate
+
+	return nil
+}
+
+func (table *TTable) dropColumn(name string) error {
+	idx, err := table.columnIndex(name)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]TValue, len(table.Rows))
+	for i, row := range table.Rows {
+		rows[i] = append(append([]TValue{}, row[:idx]...), row[idx+1:]...)
+	}
+
+	table.Columns = append(append([]*TColumn{}, table.Columns[:idx]...), table.Columns[idx+1:]...)
+	table.Rows = rows
+	table.indexes = nil
+	table.dropColumnConstraints(name)
+
+	return nil
+}
+
+func (table *TTable) renameColumn(name string, newName string) error {
+	idx, err := table.columnIndex(name)
+	if err != nil {
+		return err
+	}
+
+	if table.Column(newName) != nil {
+		return fmt.Errorf("%w: %s in table %s", ErrDuplicateColumn, newName, table.Name)
+	}
+
+	renamed := *table.Columns[idx]
+	renamed.Name = newName
+
+	table.Columns = append([]*TColumn{}, table.Columns...)
+	table.Columns[idx] = &renamed
+	table.renameColumnConstraints(name, newName)
+
+	return nil
+}
+
+func columnOf(columnMeta *ast.TColumnMeta) (*TColumn, error) {
+	column, err := datatypeOf(columnMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	column.Default = columnMeta.Default
+	column.NotNull = columnMeta.NotNull || columnMeta.PrimaryKey
+
+	if column.Default != nil {
+		if err := rejectSubqueries(column.Default, "DEFAULT expressions"); err != nil {
+			return nil, err
+		}
+	}
+
+	return column, nil
+}
+
+func columnDefault(column *TColumn, query *tQuery) (TValue, error) {
+	if column.Default == nil {
+		return nil, nil
+	}
+
+	value, err := evaluateExpression(column.Default, &tRowContext{query: query})
+	if err != nil {
+		return nil, err
+	}
+
+	return column.coerce(value)
+}
+
+func (table *TTable) insertTargets(columns []lexer.TToken) ([]int, error) {
+	if columns == nil {
+		targets := make([]int, len(table.Columns))
+		for i := range targets {
+			targets[i] = i
+		}
+
+		return targets, nil
+	}
+
+	targets := make([]int, len(columns))
+	for i, column := range columns {
+		idx, err := table.columnIndex(column.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, target := range targets[:i] {
+			if target == idx {
+				return nil, fmt.Errorf("%w: %s specified more than once", ErrDuplicateColumn, column.Value)
+			}
+		}
+
+		targets[i] = idx
+	}
+
+	return targets, nil
+}
+
+func (table *TTable) defaultRow(query *tQuery) ([]TValue, error) {
+	row := make([]TValue, len(table.Columns))
+
+	for i, column := range table.Columns {
+		value, err := columnDefault(column, query)
+		if err != nil {
+			return nil, fmt.Errorf("%w for column %s.%s", err, table.Name, column.Name)
+		}
+
+		row[i] = value
+	}
+
+	return row, nil
+}
+
+// rowKey encodes values as a map key. Every value is length-prefixed so that
+// text cannot spill into the next value, and negative zero is folded into
+// zero because both compare equal.
+func rowKey(values []TValue) string {
+	key := strings.Builder{}
+
+	for _, value := range values {
+		if number, ok := value.(float64); ok && number == 0 {
+			value = float64(0)
+		}
+
+		encoded := fmt.Sprintf("%T:%v", value, value)
+		fmt.Fprintf(&key, "%d:%s", len(encoded), encoded)
+	}
+
+	return key.String()
+}
+
+func relationOf(table *TTable) *tRelation {
+	relation := &tRelation{rows: table.Rows}
+
+	for _, column := range table.Columns {
+		relation.columns = append(relation.columns, &tRelationColumn{
+			table: table.Name,
+			name:  column.Name,
+			typ:   column.Type,
+		})
+	}
+
+	return relation
+}
+
+func resolveColumn(columns []*tRelationColumn, table string, name string) (int, error) {
+	found := -1
+
+	for i, column := range columns {
+		if column.name != name || (table != "" && column.table != table) || (table == "" && column.hidden) {
+			continue
+		}
+
+		if found >= 0 {
+			return -1, fmt.Errorf("%w: %s", ErrAmbiguousColumn, qualifiedName(table, name))
+		}
+
+		found = i
+	}
+
+	if found < 0 {
+		return -1, fmt.Errorf("%w: %s", ErrColumnDoesNotExist, qualifiedName(table, name))
+	}
+
+	return found, nil
+}
+
+func qualifiedName(table string, name string) string {
+	if table == "" {
+		return name
+	}
+
+	return table + "." + name
+}
+
+func columnReference(expression *ast.TExpression) (string, string, bool) {
+	switch {
+	case expression.Type == ast.LiteralType && expression.Literal.Type == lexer.IdentifierType:
+		return "", expression.Literal.Value, true
+	case expression.Type == ast.QualifiedType:
+		return expression.Qualified.Table.Value, expression.Qualified.Column.Value, true
+	}
+
+	return "", "", false
+}
+
+// sameExpression reports whether two expressions compute the same value, with
+// column references compared by the column they resolve to.
+func sameExpression(a *ast.TExpression, b *ast.TExpression, columns []*tRelationColumn) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if table, name, ok := columnReference(a); ok {
+		otherTable, otherName, ok := columnReference(b)
+		if !ok {
+			return false
+		}
+
+		idx, err := resolveColumn(columns, table, name)
+		otherIdx, otherErr := resolveColumn(columns, otherTable, otherName)
+
+		return err == nil && otherErr == nil && idx == otherIdx
+	}
+
+	if a.Type != b.Type {
+		return false
+	}
+
+	switch a.Type {
+	case ast.LiteralType:
+		return a.Literal.Type == b.Literal.Type && a.Literal.Value == b.Literal.Value
+	case ast.BinaryType:
+		return a.Binary.Operator.Value == b.Binary.Operator.Value &&
+			sameExpression(a.Binary.A, b.Binary.A, columns) &&
+			sameExpression(a.Binary.B, b.Binary.B, columns)
+	case ast.UnaryType:
+		return a.Unary.Operator.Value == b.Unary.Operator.Value &&
+			sameExpression(a.Unary.Operand, b.Unary.Operand, columns)
+	case ast.IsType:
+		return a.Is.Not == b.Is.Not && a.Is.Distinct == b.Is.Distinct &&
+			sameExpression(a.Is.A, b.Is.A, columns) &&
+			sameExpression(a.Is.B, b.Is.B, columns)
+	case ast.FunctionType:
+		return a.Function.Name.Value == b.Function.Name.Value &&
+			a.Function.Star == b.Function.Star &&
+			a.Function.Distinct == b.Function.Distinct &&
+			sameExpressions(a.Function.Arguments, b.Function.Arguments, columns)
+	case ast.SubqueryType, ast.ExistsType:
+		return a.Subquery == b.Subquery
+	case ast.InType:
+		return a.In.Not == b.In.Not && a.In.Subquery == b.In.Subquery &&
+			sameExpression(a.In.A, b.In.A, columns) &&
+			sameExpressions(a.In.List, b.In.List, columns)
+	case ast.BetweenType:
+		return a.Between.Not == b.Between.Not &&
+			sameExpressions(
+				[]*ast.TExpression{a.Between.A, a.Between.Low, a.Between.High},
+				[]*ast.TExpression{b.Between.A, b.Between.Low, b.Between.High},
+				columns,
+			)
+	case ast.LikeType:
+		return a.Like.Not == b.Like.Not && a.Like.Insensitive == b.Like.Insensitive &&
+			sameExpressions(
+				[]*ast.TExpression{a.Like.A, a.Like.Pattern, a.Like.Escape},
+				[]*ast.TExpression{b.Like.A, b.Like.Pattern, b.Like.Escape},
+				columns,
+			)
+	case ast.CaseType:
+		if len(a.Case.Whens) != len(b.Case.Whens) ||
+			!sameExpression(a.Case.Operand, b.Case.Operand, columns) ||
+			!sameExpression(a.Case.Else, b.Case.Else, columns) {
+			return false
+		}
+
+		for i, when := range a.Case.Whens {
+			if !sameExpression(when.Condition, b.Case.Whens[i].Condition, columns) ||
+				!sameExpression(when.Result, b.Case.Whens[i].Result, columns) {
+				return false
+			}
+		}
+
+		return true
+	case ast.CastType:
+		return a.Cast.Datatype.Value == b.Cast.Datatype.Value &&
+			sameTokens(a.Cast.DatatypeParams, b.Cast.DatatypeParams) &&
+			sameExpression(a.Cast.Operand, b.Cast.Operand, columns)
+	}
+
+	return false
+}
+
+func sameTokens(a []lexer.TToken, b []lexer.TToken) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Value != b[i].Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameExpressions(a []*ast.TExpression, b []*ast.TExpression, columns []*tRelationColumn) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !sameExpression(a[i], b[i], columns) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func aliasRelation(relation *tRelation, alias string) *tRelation {
+	aliased := &tRelation{rows: relation.rows}
+
+	for _, column := range relation.columns {
+		renamed := *column
+		renamed.table = alias
+		aliased.columns = append(aliased.columns, &renamed)
+	}
+
+	return aliased
+}
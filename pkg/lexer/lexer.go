@@ -5,6 +5,12 @@ import (
 	"strings"
 )
 
+const (
+	lineCommentStart  = "--"
+	blockCommentStart = "/*"
+	blockCommentEnd   = "*/"
+)
+
 func CheckSymbol(source string, inputCursor TCursor) (*TToken, TCursor, bool) {
 	if uint(len(source)) == 0 {
 		return nil, inputCursor, false
@@ -35,6 +41,20 @@ func CheckSymbol(source string, inputCursor TCursor) (*TToken, TCursor, bool) {
 		CommaToken,
 		LeftParenthToken,
 		RightParenthToken,
+		PlusToken,
+		MinusToken,
+		SlashToken,
+		PercentToken,
+		EqualToken,
+		NotEqualToken,
+		BangEqualToken,
+		LessToken,
+		LessEqualToken,
+		GreaterToken,
+		GreaterEqualToken,
+		ConcatToken,
+		DotToken,
+		DoubleColonToken,
 	}
 
 	match := matchBestOption(source, inputCursor, getStringRerp(symbols))
@@ -44,6 +64,10 @@ func CheckSymbol(source string, inputCursor TCursor) (*TToken, TCursor, bool) {
 		return nil, inputCursor, false
 	}
 
+	if end := inputCursor.CurrPos + matchLen; match == string(DotToken) && end < uint(len(source)) && isNumeric(source[end]) {
+		return nil, inputCursor, false
+	}
+
 	curr.CurrPos = inputCursor.CurrPos + matchLen
 	curr.Loc.Column = inputCursor.Loc.Column + matchLen
 
@@ -64,6 +88,75 @@ func CheckReservedToken(source string, inputCursor TCursor) (*TToken, TCursor, b
 		ValuesToken,
 		IntToken,
 		TextToken,
+		AndToken,
+		OrToken,
+		NotToken,
+		WhereToken,
+		UpdateToken,
+		SetToken,
+		DeleteToken,
+		DropToken,
+		AlterToken,
+		AddToken,
+		ColumnToken,
+		RenameToken,
+		ToToken,
+		IfToken,
+		ExistsToken,
+		DefaultToken,
+		PrimaryToken,
+		UniqueToken,
+		CheckToken,
+		NullToken,
+		TrueToken,
+		FalseToken,
+		IsToken,
+		DistinctToken,
+		OrderToken,
+		ByToken,
+		AscToken,
+		DescToken,
+		NullsToken,
+		LimitToken,
+		OffsetToken,
+		GroupToken,
+		HavingToken,
+		JoinToken,
+		InnerToken,
+		LeftToken,
+		RightToken,
+		FullToken,
+		OuterToken,
+		CrossToken,
+		OnToken,
+		UsingToken,
+		InToken,
+		BetweenToken,
+		LikeToken,
+		ILikeToken,
+		EscapeToken,
+		CaseToken,
+		WhenToken,
+		ThenToken,
+		ElseToken,
+		EndToken,
+		CastToken,
+		UnionToken,
+		IntersectToken,
+		ExceptToken,
+		AllToken,
+		IntegerToken,
+		BigIntToken,
+		SmallIntToken,
+		BooleanToken,
+		RealToken,
+		DoubleToken,
+		PrecisionToken,
+		NumericToken,
+		DecimalToken,
+		VarcharToken,
+		TimestampToken,
+		BlobToken,
 	}
 
 	match := matchBestOption(source, inputCursor, getStringRerp(reservedTokens))
@@ -73,10 +166,14 @@ func CheckReservedToken(source string, inputCursor TCursor) (*TToken, TCursor, b
 		return nil, inputCursor, false
 	}
 
+	if end := inputCursor.CurrPos + matchLen; end < uint(len(source)) && isIdentifierPart(source[end]) {
+		return nil, inputCursor, false
+	}
+
 	curr.CurrPos = inputCursor.CurrPos + matchLen
 	curr.Loc.Column = inputCursor.Loc.Column + matchLen
 
-	return &TToken{Value: match, Type: ReservedType, Loc: inputCursor.Loc}, curr, matchLen > 0
+	return &TToken{Value: match, Type: TReservedToken(match).tokenType(), Loc: inputCursor.Loc}, curr, matchLen > 0
 }
 
 func CheckNumeric(source string, inputCursor TCursor) (*TToken, TCursor, bool) {
@@ -85,9 +182,8 @@ func CheckNumeric(source string, inputCursor TCursor) (*TToken, TCursor, bool) {
 	isFloat := false
 	isExponent := false
 
-	for ; curr.CurrPos < uint(len(source)); curr.CurrPos++ {
+	for ; curr.CurrPos < uint(len(source)); curr.CurrPos, curr.Loc.Column = curr.CurrPos+1, curr.Loc.Column+1 {
 		currChar := source[curr.CurrPos]
-		curr.Loc.Column++
 
 		isDigit := isNumeric(currChar)
 		isPeriod := currChar == '.'
@@ -168,10 +264,7 @@ func CheckIdentifier(source string, inputCursor TCursor) (*TToken, TCursor, bool
 	for ; curr.CurrPos < uint(len(source)); curr.CurrPos++ {
 		currChar := source[curr.CurrPos]
 
-		isAlpha := isLetter(currChar)
-		isNumeric := isNumeric(currChar)
-
-		if isAlpha || isNumeric || currChar == '$' || currChar == '_' {
+		if isIdentifierPart(currChar) {
 			match = append(match, currChar)
 			curr.Loc.Column++
 			continue
@@ -191,21 +284,100 @@ func CheckString(source string, inputCursor TCursor) (*TToken, TCursor, bool) {
 	return checkDelimeted(source, inputCursor, '\'')
 }
 
+func CheckComment(source string, inputCursor TCursor) (*TToken, TCursor, bool) {
+	rest := source[inputCursor.CurrPos:]
+	curr := inputCursor
+
+	switch {
+	case strings.HasPrefix(rest, lineCommentStart):
+		end := strings.IndexByte(rest, '\n')
+		if end < 0 {
+			end = len(rest)
+		}
+
+		curr.CurrPos += uint(end)
+		curr.Loc.Column += uint(end)
+	case strings.HasPrefix(rest, blockCommentStart):
+		depth := 0
+
+		for curr.CurrPos < uint(len(source)) {
+			switch {
+			case strings.HasPrefix(source[curr.CurrPos:], blockCommentStart):
+				depth++
+				curr.CurrPos += 2
+				curr.Loc.Column += 2
+			case strings.HasPrefix(source[curr.CurrPos:], blockCommentEnd):
+				depth--
+				curr.CurrPos += 2
+				curr.Loc.Column += 2
+			case source[curr.CurrPos] == '\n':
+				curr.CurrPos++
+				curr.Loc.Line++
+				curr.Loc.Column = 0
+			default:
+				curr.CurrPos++
+				curr.Loc.Column++
+			}
+
+			if depth == 0 {
+				break
+			}
+		}
+
+		if depth > 0 {
+			return nil, inputCursor, false
+		}
+	default:
+		return nil, inputCursor, false
+	}
+
+	return &TToken{
+		Value: source[inputCursor.CurrPos:curr.CurrPos],
+		Type:  CommentType,
+		Loc:   inputCursor.Loc,
+	}, curr, true
+}
+
+func (err *LexError) Error() string {
+	return fmt.Sprintf("%s, at %d:%d", err.Message, err.Cursor.Loc.Line, err.Cursor.Loc.Column)
+}
+
 type apply func(string, TCursor) (*TToken, TCursor, bool)
 
 func Tokenize(source string) ([]*TToken, error) {
+	return TokenizeWithOptions(source, TTokenizeOptions{})
+}
+
+func TokenizeWithOptions(source string, options TTokenizeOptions) ([]*TToken, error) {
 	tokens := []*TToken{}
 	curr := TCursor{}
 
 Tokenize:
 	for curr.CurrPos < uint(len(source)) {
-		lexers := []apply{CheckReservedToken, CheckSymbol, CheckString, CheckNumeric, CheckIdentifier}
+		lexers := []apply{CheckComment, CheckReservedToken, CheckSymbol, CheckString, CheckNumeric, CheckIdentifier}
+
+		if strings.HasPrefix(source[curr.CurrPos:], blockCommentStart) {
+			if _, _, ok := CheckComment(source, curr); !ok {
+				return nil, &LexError{Cursor: curr, Message: "Unterminated block comment", Unterminated: true}
+			}
+		}
+
+		switch source[curr.CurrPos] {
+		case '\'':
+			if _, _, ok := CheckString(source, curr); !ok {
+				return nil, &LexError{Cursor: curr, Message: "Unterminated string", Unterminated: true}
+			}
+		case '"':
+			if _, _, ok := CheckIdentifier(source, curr); !ok {
+				return nil, &LexError{Cursor: curr, Message: "Unterminated quoted identifier", Unterminated: true}
+			}
+		}
 
 		for _, lexer := range lexers {
 			if token, currCursor, ok := lexer(source, curr); ok {
 				curr = currCursor
 
-				if token != nil {
+				if token != nil && (token.Type != CommentType || options.KeepComments) {
 					tokens = append(tokens, token)
 				}
 
@@ -213,11 +385,11 @@ Tokenize:
 			}
 		}
 
-		hint := ""
+		message := "Unable to lex token"
 		if len(tokens) > 0 {
-			hint = "after " + tokens[len(tokens)-1].Value
+			message += fmt.Sprintf(" after %q", tokens[len(tokens)-1].Value)
 		}
-		return nil, fmt.Errorf("Unable to lex token %s, at %d:%d", hint, curr.Loc.Line, curr.Loc.Column)
+		return nil, &LexError{Cursor: curr, Message: message}
 	}
 
 	return tokens, nil
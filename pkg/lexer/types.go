@@ -14,16 +14,90 @@ type TTokenLocation struct {
 }
 
 const (
-	SelectToken TReservedToken = "select"
-	FromToken   TReservedToken = "from"
-	CreateToken TReservedToken = "create"
-	TableToken  TReservedToken = "table"
-	AsToken     TReservedToken = "as"
-	InsertToken TReservedToken = "insert"
-	IntoToken   TReservedToken = "into"
-	ValuesToken TReservedToken = "values"
-	IntToken    TReservedToken = "int"
-	TextToken   TReservedToken = "text"
+	SelectToken    TReservedToken = "select"
+	FromToken      TReservedToken = "from"
+	CreateToken    TReservedToken = "create"
+	TableToken     TReservedToken = "table"
+	AsToken        TReservedToken = "as"
+	InsertToken    TReservedToken = "insert"
+	IntoToken      TReservedToken = "into"
+	ValuesToken    TReservedToken = "values"
+	IntToken       TReservedToken = "int"
+	TextToken      TReservedToken = "text"
+	AndToken       TReservedToken = "and"
+	OrToken        TReservedToken = "or"
+	NotToken       TReservedToken = "not"
+	WhereToken     TReservedToken = "where"
+	UpdateToken    TReservedToken = "update"
+	SetToken       TReservedToken = "set"
+	DeleteToken    TReservedToken = "delete"
+	DropToken      TReservedToken = "drop"
+	AlterToken     TReservedToken = "alter"
+	AddToken       TReservedToken = "add"
+	ColumnToken    TReservedToken = "column"
+	RenameToken    TReservedToken = "rename"
+	ToToken        TReservedToken = "to"
+	IfToken        TReservedToken = "if"
+	ExistsToken    TReservedToken = "exists"
+	DefaultToken   TReservedToken = "default"
+	PrimaryToken   TReservedToken = "primary"
+	KeyToken       TReservedToken = "key"
+	UniqueToken    TReservedToken = "unique"
+	CheckToken     TReservedToken = "check"
+	NullToken      TReservedToken = "null"
+	TrueToken      TReservedToken = "true"
+	FalseToken     TReservedToken = "false"
+	IsToken        TReservedToken = "is"
+	DistinctToken  TReservedToken = "distinct"
+	OrderToken     TReservedToken = "order"
+	ByToken        TReservedToken = "by"
+	AscToken       TReservedToken = "asc"
+	DescToken      TReservedToken = "desc"
+	NullsToken     TReservedToken = "nulls"
+	FirstToken     TReservedToken = "first"
+	LastToken      TReservedToken = "last"
+	LimitToken     TReservedToken = "limit"
+	OffsetToken    TReservedToken = "offset"
+	GroupToken     TReservedToken = "group"
+	HavingToken    TReservedToken = "having"
+	JoinToken      TReservedToken = "join"
+	InnerToken     TReservedToken = "inner"
+	LeftToken      TReservedToken = "left"
+	RightToken     TReservedToken = "right"
+	FullToken      TReservedToken = "full"
+	OuterToken     TReservedToken = "outer"
+	CrossToken     TReservedToken = "cross"
+	OnToken        TReservedToken = "on"
+	UsingToken     TReservedToken = "using"
+	InToken        TReservedToken = "in"
+	BetweenToken   TReservedToken = "between"
+	LikeToken      TReservedToken = "like"
+	ILikeToken     TReservedToken = "ilike"
+	EscapeToken    TReservedToken = "escape"
+	CaseToken      TReservedToken = "case"
+	WhenToken      TReservedToken = "when"
+	ThenToken      TReservedToken = "then"
+	ElseToken      TReservedToken = "else"
+	EndToken       TReservedToken = "end"
+	CastToken      TReservedToken = "cast"
+	UnionToken     TReservedToken = "union"
+	IntersectToken TReservedToken = "intersect"
+	ExceptToken    TReservedToken = "except"
+	AllToken       TReservedToken = "all"
+
+	IntegerToken   TReservedToken = "integer"
+	BigIntToken    TReservedToken = "bigint"
+	SmallIntToken  TReservedToken = "smallint"
+	BooleanToken   TReservedToken = "boolean"
+	RealToken      TReservedToken = "real"
+	DoubleToken    TReservedToken = "double"
+	PrecisionToken TReservedToken = "precision"
+	NumericToken   TReservedToken = "numeric"
+	DecimalToken   TReservedToken = "decimal"
+	VarcharToken   TReservedToken = "varchar"
+	DateToken      TReservedToken = "date"
+	TimestampToken TReservedToken = "timestamp"
+	BlobToken      TReservedToken = "blob"
 )
 
 const (
@@ -32,6 +106,20 @@ const (
 	CommaToken        TSymbolToken = ","
 	LeftParenthToken  TSymbolToken = "("
 	RightParenthToken TSymbolToken = ")"
+	PlusToken         TSymbolToken = "+"
+	MinusToken        TSymbolToken = "-"
+	SlashToken        TSymbolToken = "/"
+	PercentToken      TSymbolToken = "%"
+	EqualToken        TSymbolToken = "="
+	NotEqualToken     TSymbolToken = "<>"
+	BangEqualToken    TSymbolToken = "!="
+	LessToken         TSymbolToken = "<"
+	LessEqualToken    TSymbolToken = "<="
+	GreaterToken      TSymbolToken = ">"
+	GreaterEqualToken TSymbolToken = ">="
+	ConcatToken       TSymbolToken = "||"
+	DotToken          TSymbolToken = "."
+	DoubleColonToken  TSymbolToken = "::"
 )
 
 const (
@@ -40,6 +128,9 @@ const (
 	IdentifierType
 	StringType
 	NumericType
+	BooleanType
+	NullType
+	CommentType
 )
 
 type TToken struct {
@@ -48,7 +139,19 @@ type TToken struct {
 	Loc   TTokenLocation
 }
 
+type TTokenizeOptions struct {
+	KeepComments bool
+}
+
 type TCursor struct {
 	CurrPos uint
 	Loc     TTokenLocation
 }
+
+type LexError struct {
+	Cursor  TCursor
+	Message string
+	// Unterminated is set when the source ends inside a string, quoted
+	// identifier or block comment, so more input could still complete it.
+	Unterminated bool
+}
@@ -81,7 +81,13 @@ func checkDelimeted(source string, inputCursor TCursor, delimeter byte) (*TToken
 		}
 
 		resMatch = append(resMatch, currChar)
-		curr.Loc.Column++
+
+		if currChar == '\n' {
+			curr.Loc.Line++
+			curr.Loc.Column = 0
+		} else {
+			curr.Loc.Column++
+		}
 	}
 
 	return nil, inputCursor, false
@@ -118,6 +124,10 @@ func isLetter(char byte) bool {
 	return (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z')
 }
 
+func isIdentifierPart(char byte) bool {
+	return isLetter(char) || isNumeric(char) || char == '$' || char == '_'
+}
+
 func (token *TToken) Equal(other *TToken) bool {
 	return token.Value == other.Value && token.Type == other.Type
 }
@@ -125,10 +135,25 @@ func (token *TToken) Equal(other *TToken) bool {
 func (reservedToken TReservedToken) AsToken() *TToken {
 	return &TToken{
 		Value: string(reservedToken),
-		Type:  ReservedType,
+		Type:  reservedToken.tokenType(),
 	}
 }
 
+func (reservedToken TReservedToken) tokenType() ETokenType {
+	switch reservedToken {
+	case TrueToken, FalseToken:
+		return BooleanType
+	case NullToken:
+		return NullType
+	case KeyToken, FirstToken, LastToken, DateToken:
+		// Non-reserved keywords lex as identifiers so that they can still
+		// name tables and columns.
+		return IdentifierType
+	}
+
+	return ReservedType
+}
+
 func (symbolToken TSymbolToken) AsToken() *TToken {
 	return &TToken{
 		Value: string(symbolToken),
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"pkg/lexer"
+)
+
+type ParseError struct {
+	Line     uint
+	Column   uint
+	Offset   uint
+	Token    *lexer.TToken
+	Expected []string
+	Message  string
+	Excerpt  string
+}
+
+type tParser struct {
+	source  string
+	tokens  []*lexer.TToken
+	end     uint
+	failure *tFailure
+}
+
+type tFailure struct {
+	cursor   uint
+	expected []string
+	message  string
+}
+
+func (err *ParseError) Error() string {
+	message := err.Message
+
+	if len(err.Expected) > 0 {
+		got := "end of input"
+		if err.Token != nil {
+			got = fmt.Sprintf("%q", err.Token.Value)
+		}
+
+		message = fmt.Sprintf("Expected %s, got %s", joinExpected(err.Expected), got)
+	}
+
+	return fmt.Sprintf("%s, at %d:%d", message, err.Line, err.Column)
+}
+
+func joinExpected(expected []string) string {
+	if len(expected) == 1 {
+		return expected[0]
+	}
+
+	return strings.Join(expected[:len(expected)-1], ", ") + " or " + expected[len(expected)-1]
+}
+
+func (p *tParser) failAt(cursor uint) *tFailure {
+	if p.failure == nil || cursor > p.failure.cursor {
+		p.failure = &tFailure{cursor: cursor}
+	}
+
+	if cursor < p.failure.cursor {
+		return nil
+	}
+
+	return p.failure
+}
+
+func (p *tParser) expect(cursor uint, expected ...string) {
+	failure := p.failAt(cursor)
+	if failure == nil {
+		return
+	}
+
+expected:
+	for _, candidate := range expected {
+		for _, known := range failure.expected {
+			if known == candidate {
+				continue expected
+			}
+		}
+
+		failure.expected = append(failure.expected, candidate)
+	}
+}
+
+func (p *tParser) expectTokens(cursor uint, expected ...lexer.TToken) {
+	for _, token := range expected {
+		label := fmt.Sprintf("%q", token.Value)
+		if token.Type != lexer.SymbolType {
+			label = strings.ToUpper(token.Value)
+		}
+
+		p.expect(cursor, label)
+	}
+}
+
+func (p *tParser) reject(cursor uint, message string) {
+	if failure := p.failAt(cursor); failure != nil && failure.message == "" {
+		failure.message = message
+	}
+}
+
+func (p *tParser) error() *ParseError {
+	failure := p.failure
+	if failure == nil {
+		failure = &tFailure{message: "Failed to parse"}
+	}
+
+	err := &ParseError{
+		Expected: failure.expected,
+		Message:  failure.message,
+	}
+
+	if failure.cursor < p.end {
+		err.Token = p.tokens[failure.cursor]
+		err.Line = err.Token.Loc.Line
+		err.Column = err.Token.Loc.Column
+		err.Offset = offsetOf(p.source, err.Token.Loc)
+	} else {
+		err.Offset = uint(len(strings.TrimRight(p.source, " \t\r\n")))
+		err.Line, err.Column = locationOf(p.source, err.Offset)
+	}
+
+	err.Excerpt = excerpt(p.source, err.Offset)
+
+	return err
+}
+
+func lexError(source string, err error) error {
+	var lexErr *lexer.LexError
+	if !errors.As(err, &lexErr) {
+		return err
+	}
+
+	return &ParseError{
+		Line:    lexErr.Cursor.Loc.Line,
+		Column:  lexErr.Cursor.Loc.Column,
+		Offset:  lexErr.Cursor.CurrPos,
+		Message: lexErr.Message,
+		Excerpt: excerpt(source, lexErr.Cursor.CurrPos),
+	}
+}
+
+func offsetOf(source string, loc lexer.TTokenLocation) uint {
+	offset := uint(0)
+
+	for line := uint(0); line < loc.Line; line++ {
+		next := strings.IndexByte(source[offset:], '\n')
+		if next < 0 {
+			break
+		}
+
+		offset += uint(next) + 1
+	}
+
+	return min(offset+loc.Column, uint(len(source)))
+}
+
+func locationOf(source string, offset uint) (uint, uint) {
+	prefix := source[:offset]
+	lineStart := strings.LastIndexByte(prefix, '\n') + 1
+
+	return uint(strings.Count(prefix, "\n")), offset - uint(lineStart)
+}
+
+func excerpt(source string, offset uint) string {
+	lineStart := strings.LastIndexByte(source[:offset], '\n') + 1
+
+	lineEnd := len(source)
+	if next := strings.IndexByte(source[offset:], '\n'); next >= 0 {
+		lineEnd = int(offset) + next
+	}
+
+	caret := strings.Map(func(char rune) rune {
+		if char == '\t' {
+			return char
+		}
+
+		return ' '
+	}, source[lineStart:offset])
+
+	return strings.TrimRight(source[lineStart:lineEnd], "\r") + "\n" + caret + "^"
+}
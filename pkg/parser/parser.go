@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"errors"
 	"pkg/ast"
 	"pkg/lexer"
 )
@@ -9,39 +8,35 @@ import (
 func Parse(source string) (*ast.TSyntaxTree, error) {
 	tokens, err := lexer.Tokenize(source)
 	if err != nil {
-		return nil, err
+		return nil, lexError(source, err)
 	}
 
+	p := &tParser{source: source, tokens: tokens, end: uint(len(tokens))}
+
 	semicolonToken := lexer.SemicolonToken.AsToken()
-	if len(tokens) > 0 && !tokens[len(tokens)-1].Equal(semicolonToken) {
-		tokens = append(tokens, semicolonToken)
+	if len(p.tokens) > 0 && !p.tokens[len(p.tokens)-1].Equal(semicolonToken) {
+		p.tokens = append(p.tokens, semicolonToken)
 	}
 
 	syntaxTree := ast.TSyntaxTree{}
 	curr := uint(0)
 
-	for curr < uint(len(tokens)) {
-		statement, currCursor, ok := parseStatement(tokens, curr, *semicolonToken)
+	for curr < uint(len(p.tokens)) {
+		statement, currCursor, ok := p.parseStatement(curr, *semicolonToken)
 		if !ok {
-			logInfo(tokens, curr, "Expected statement")
-			return nil, errors.New("Failed to parse, expected statement")
+			p.expect(curr, "statement")
+			return nil, p.error()
 		}
 		curr = currCursor
 
 		syntaxTree.Statements = append(syntaxTree.Statements, statement)
 
-		hasSemicolon := false
-		for {
-			_, curr, hasSemicolon = parseToken(tokens, curr, *semicolonToken)
-			if hasSemicolon {
-				break
-			}
-		}
-
+		_, currCursor, hasSemicolon := p.parseToken(curr, *semicolonToken)
 		if !hasSemicolon {
-			logInfo(tokens, curr, "Expected semi-colon delimiter between statements")
-			return nil, errors.New("Missing semi-colon between statements")
+			p.expectTokens(curr, *semicolonToken)
+			return nil, p.error()
 		}
+		curr = currCursor
 	}
 
 	return &syntaxTree, nil
@@ -2,345 +2,2161 @@ package parser
 
 import (
 	"fmt"
+	"strings"
+
 	"pkg/ast"
 	"pkg/lexer"
 )
 
-func logInfo(tokens []*lexer.TToken, cursor uint, message string) {
-	var curr *lexer.TToken
+const (
+	lowestPower uint = iota
+	orPower
+	andPower
+	notPower
+	isPower
+	comparisonPower
+	predicatePower
+	concatPower
+	additivePower
+	multiplicativePower
+	unaryPower
+	castPower
+)
+
+const extractFunction = "extract"
+
+var datatypes = []lexer.TReservedToken{
+	lexer.IntToken,
+	lexer.IntegerToken,
+	lexer.SmallIntToken,
+	lexer.BigIntToken,
+	lexer.TextToken,
+	lexer.VarcharToken,
+	lexer.BooleanToken,
+	lexer.RealToken,
+	lexer.DoubleToken,
+	lexer.NumericToken,
+	lexer.DecimalToken,
+	lexer.DateToken,
+	lexer.TimestampToken,
+	lexer.BlobToken,
+}
+
+var unaryOperators = []*lexer.TToken{
+	lexer.MinusToken.AsToken(),
+	lexer.PlusToken.AsToken(),
+	lexer.NotToken.AsToken(),
+}
+
+func binaryOperatorPower(token *lexer.TToken) uint {
+	switch token.Type {
+	case lexer.ReservedType:
+		switch lexer.TReservedToken(token.Value) {
+		case lexer.OrToken:
+			return orPower
+		case lexer.AndToken:
+			return andPower
+		case lexer.IsToken:
+			return isPower
+		case lexer.InToken, lexer.BetweenToken, lexer.LikeToken, lexer.ILikeToken:
+			return predicatePower
+		}
+	case lexer.SymbolType:
+		switch lexer.TSymbolToken(token.Value) {
+		case lexer.EqualToken, lexer.NotEqualToken, lexer.BangEqualToken,
+			lexer.LessToken, lexer.LessEqualToken, lexer.GreaterToken, lexer.GreaterEqualToken:
+			return comparisonPower
+		case lexer.ConcatToken:
+			return concatPower
+		case lexer.PlusToken, lexer.MinusToken:
+			return additivePower
+		case lexer.AsteriksToken, lexer.SlashToken, lexer.PercentToken:
+			return multiplicativePower
+		case lexer.DoubleColonToken:
+			return castPower
+		}
+	}
+
+	return lowestPower
+}
+
+var negatablePredicates = []lexer.TReservedToken{
+	lexer.InToken,
+	lexer.BetweenToken,
+	lexer.LikeToken,
+	lexer.ILikeToken,
+}
+
+// operatorPower is binaryOperatorPower extended with infix NOT, which only
+// binds as an operator when it negates a following predicate, so that e.g.
+// DEFAULT 0 NOT NULL still ends the default expression before NOT.
+func (p *tParser) operatorPower(cursor uint) uint {
+	for _, predicate := range negatablePredicates {
+		if _, ok := p.parseKeywords(cursor, lexer.NotToken, predicate); ok {
+			return predicatePower
+		}
+	}
+
+	return binaryOperatorPower(p.tokens[cursor])
+}
+
+func isDelimeter(candidate *lexer.TToken, delimeters *[]lexer.TToken) bool {
+	for _, delimeter := range *delimeters {
+		if delimeter.Equal(candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *tParser) parseTokenType(
+	initialCursor uint,
+	ttype lexer.ETokenType,
+) (*lexer.TToken, uint, bool) {
+	cursor := initialCursor
+
+	if cursor >= uint(len(p.tokens)) {
+		return nil, initialCursor, false
+	}
+
+	if currToken := p.tokens[cursor]; currToken.Type == ttype {
+		return currToken, cursor + 1, true
+	}
+
+	return nil, initialCursor, false
+}
+
+func (p *tParser) parseToken(inputCursor uint, candToken lexer.TToken) (*lexer.TToken, uint, bool) {
+	curr := inputCursor
+
+	if curr >= uint(len(p.tokens)) {
+		return nil, inputCursor, false
+	}
+
+	if currToken := p.tokens[curr]; candToken.Equal(currToken) {
+		return currToken, curr + 1, true
+	}
+
+	return nil, inputCursor, false
+}
+
+func (p *tParser) parseExpression(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+	minPower uint,
+) (*ast.TExpression, uint, bool) {
+	expression, curr, ok := p.parsePrefixExpression(inputCursor, delimeters)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	for curr < uint(len(p.tokens)) {
+		operator := p.tokens[curr]
+		if isDelimeter(operator, &delimeters) {
+			break
+		}
+
+		power := p.operatorPower(curr)
+		if power <= minPower {
+			break
+		}
+
+		if power == predicatePower {
+			predicate, currCursor, ok := p.parsePredicate(curr, delimeters, expression)
+			if !ok {
+				return nil, inputCursor, false
+			}
+			curr = currCursor
+
+			expression = predicate
+			continue
+		}
+
+		if power == castPower {
+			datatype, params, currCursor, ok := p.parseDatatype(curr + 1)
+			if !ok {
+				p.expect(curr+1, "datatype")
+				return nil, inputCursor, false
+			}
+			curr = currCursor
+
+			expression = &ast.TExpression{
+				Cast: &ast.TCastExpression{
+					Operand:        expression,
+					Datatype:       *datatype,
+					DatatypeParams: params,
+				},
+				Type: ast.CastType,
+			}
+			continue
+		}
+
+		if power == isPower {
+			isExpression, currCursor, ok := p.parseIsExpression(curr, delimeters, expression)
+			if !ok {
+				return nil, inputCursor, false
+			}
+			curr = currCursor
+
+			expression = isExpression
+			continue
+		}
+
+		rhs, currCursor, ok := p.parseExpression(curr+1, delimeters, power)
+		if !ok {
+			p.expect(curr+1, "expression")
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		expression = &ast.TExpression{
+			Binary: &ast.TBinaryExpression{
+				A:        expression,
+				B:        rhs,
+				Operator: *operator,
+			},
+			Type: ast.BinaryType,
+		}
+	}
+
+	return expression, curr, true
+}
+
+func (p *tParser) parsePrefixExpression(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+) (*ast.TExpression, uint, bool) {
+	curr := inputCursor
+
+	if p.isSubquery(curr) {
+		subquery, currCursor, ok := p.parseSubquery(curr)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		return &ast.TExpression{Subquery: subquery, Type: ast.SubqueryType}, currCursor, true
+	}
+
+	if _, ok := p.parseKeywords(curr, lexer.CaseToken); ok {
+		return p.parseCaseExpression(curr)
+	}
+
+	if _, ok := p.parseKeywords(curr, lexer.CastToken); ok {
+		return p.parseCastExpression(curr)
+	}
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.ExistsToken); ok {
+		subquery, currCursor, ok := p.parseSubquery(currCursor)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		return &ast.TExpression{Subquery: subquery, Type: ast.ExistsType}, currCursor, true
+	}
+
+	if _, currCursor, ok := p.parseToken(curr, *lexer.LeftParenthToken.AsToken()); ok {
+		rightParenthToken := *lexer.RightParenthToken.AsToken()
+
+		expression, currCursor, ok := p.parseExpression(currCursor, []lexer.TToken{rightParenthToken}, lowestPower)
+		if !ok {
+			p.expect(currCursor, "expression")
+			return nil, inputCursor, false
+		}
+
+		_, currCursor, ok = p.parseToken(currCursor, rightParenthToken)
+		if !ok {
+			p.expect(currCursor, `")"`)
+			return nil, inputCursor, false
+		}
+
+		return expression, currCursor, true
+	}
+
+	for _, operator := range unaryOperators {
+		operatorToken, currCursor, ok := p.parseToken(curr, *operator)
+		if !ok {
+			continue
+		}
+
+		power := unaryPower
+		if operator.Type == lexer.ReservedType {
+			power = notPower
+		}
+
+		operand, currCursor, ok := p.parseExpression(currCursor, delimeters, power)
+		if !ok {
+			p.expect(currCursor, "expression")
+			return nil, inputCursor, false
+		}
+
+		// Negative numbers are folded into the literal, so that the most
+		// negative BIGINT does not overflow before it is negated.
+		isNumber := operand.Type == ast.LiteralType && operand.Literal.Type == lexer.NumericType
+		if isNumber && operator.Value == string(lexer.MinusToken) && !strings.HasPrefix(operand.Literal.Value, "-") {
+			return &ast.TExpression{
+				Literal: &lexer.TToken{
+					Value: operatorToken.Value + operand.Literal.Value,
+					Type:  lexer.NumericType,
+					Loc:   operatorToken.Loc,
+				},
+				Type: ast.LiteralType,
+			}, currCursor, true
+		}
+
+		return &ast.TExpression{
+			Unary: &ast.TUnaryExpression{
+				Operand:  operand,
+				Operator: *operatorToken,
+			},
+			Type: ast.UnaryType,
+		}, currCursor, true
+	}
+
+	if table, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType); ok {
+		if _, _, ok := p.parseToken(currCursor, *lexer.LeftParenthToken.AsToken()); ok {
+			return p.parseFunctionCall(currCursor, table)
+		}
+
+		if _, currCursor, ok := p.parseToken(currCursor, *lexer.DotToken.AsToken()); ok {
+			column, currCursor, ok := p.parseTokenType(currCursor, lexer.IdentifierType)
+			if !ok {
+				p.expect(currCursor, "column name")
+				return nil, inputCursor, false
+			}
+
+			return &ast.TExpression{
+				Qualified: &ast.TQualifiedColumn{Table: *table, Column: *column},
+				Type:      ast.QualifiedType,
+			}, currCursor, true
+		}
+
+		return &ast.TExpression{
+			Literal: table,
+			Type:    ast.LiteralType,
+		}, currCursor, true
+	}
+
+	types := []lexer.ETokenType{
+		lexer.NumericType,
+		lexer.StringType,
+		lexer.BooleanType,
+		lexer.NullType,
+	}
+
+	for _, ttype := range types {
+		if currToken, currCursor, ok := p.parseTokenType(curr, ttype); ok {
+			return &ast.TExpression{
+				Literal: currToken,
+				Type:    ast.LiteralType,
+			}, currCursor, true
+		}
+	}
+
+	return nil, inputCursor, false
+}
+
+func (p *tParser) parseFunctionCall(
+	inputCursor uint,
+	name *lexer.TToken,
+) (*ast.TExpression, uint, bool) {
+	curr := inputCursor
+	rightParenthToken := *lexer.RightParenthToken.AsToken()
+
+	_, curr, ok := p.parseToken(curr, *lexer.LeftParenthToken.AsToken())
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	call := &ast.TFunctionCall{Name: *name}
+
+	if _, currCursor, ok := p.parseToken(curr, *lexer.AsteriksToken.AsToken()); ok {
+		call.Star = true
+		curr = currCursor
+	} else if arguments, currCursor, ok := p.parseExtractArguments(curr, name); ok {
+		call.Arguments = arguments
+		curr = currCursor
+	} else {
+		curr, call.Distinct = p.parseKeywords(curr, lexer.DistinctToken)
+
+		arguments, currCursor, ok := p.parseExpressions(curr, []lexer.TToken{rightParenthToken})
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		call.Arguments = *arguments
+		curr = currCursor
+	}
+
+	_, curr, ok = p.parseToken(curr, rightParenthToken)
+	if !ok {
+		p.expect(curr, `")"`)
+		return nil, inputCursor, false
+	}
+
+	return &ast.TExpression{
+		Function: call,
+		Type:     ast.FunctionType,
+	}, curr, true
+}
+
+// parseExtractArguments parses the field FROM source arguments of EXTRACT
+// into the field name as a string and the source expression.
+func (p *tParser) parseExtractArguments(
+	inputCursor uint,
+	name *lexer.TToken,
+) ([]*ast.TExpression, uint, bool) {
+	if name.Value != extractFunction {
+		return nil, inputCursor, false
+	}
+
+	field, curr, ok := p.parseTokenType(inputCursor, lexer.IdentifierType)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	curr, ok = p.parseKeywords(curr, lexer.FromToken)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	source, curr, ok := p.parseExpression(curr, []lexer.TToken{*lexer.RightParenthToken.AsToken()}, lowestPower)
+	if !ok {
+		p.expect(curr, "expression")
+		return nil, inputCursor, false
+	}
+
+	fieldLiteral := *field
+	fieldLiteral.Type = lexer.StringType
+
+	return []*ast.TExpression{{Literal: &fieldLiteral, Type: ast.LiteralType}, source}, curr, true
+}
+
+func (p *tParser) isSubquery(inputCursor uint) bool {
+	_, curr, ok := p.parseToken(inputCursor, *lexer.LeftParenthToken.AsToken())
+	if !ok {
+		return false
+	}
+
+	_, ok = p.parseKeywords(curr, lexer.SelectToken)
+	return ok
+}
+
+func (p *tParser) parseSubquery(inputCursor uint) (*ast.TSelectStatement, uint, bool) {
+	rightParenthToken := *lexer.RightParenthToken.AsToken()
+
+	_, curr, ok := p.parseToken(inputCursor, *lexer.LeftParenthToken.AsToken())
+	if !ok {
+		p.expect(inputCursor, `"("`)
+		return nil, inputCursor, false
+	}
+
+	if _, ok := p.parseKeywords(curr, lexer.SelectToken); !ok {
+		p.expect(curr, "SELECT")
+		return nil, inputCursor, false
+	}
+
+	statement, curr, ok := p.parseSelectStatement(curr, rightParenthToken)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	_, curr, ok = p.parseToken(curr, rightParenthToken)
+	if !ok {
+		p.expect(curr, `")"`)
+		return nil, inputCursor, false
+	}
+
+	return statement, curr, true
+}
+
+// parsePredicate parses [NOT] IN, BETWEEN, LIKE and ILIKE applied to an
+// already parsed operand.
+func (p *tParser) parsePredicate(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+	operand *ast.TExpression,
+) (*ast.TExpression, uint, bool) {
+	curr, not := p.parseKeywords(inputCursor, lexer.NotToken)
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.InToken); ok {
+		expression, currCursor, ok := p.parseInExpression(currCursor, operand, not)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		return expression, currCursor, true
+	}
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.BetweenToken); ok {
+		expression, currCursor, ok := p.parseBetweenExpression(currCursor, delimeters, operand, not)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		return expression, currCursor, true
+	}
+
+	for _, keyword := range []lexer.TReservedToken{lexer.LikeToken, lexer.ILikeToken} {
+		if currCursor, ok := p.parseKeywords(curr, keyword); ok {
+			expression, currCursor, ok := p.parseLikeExpression(currCursor, delimeters, operand, not)
+			if !ok {
+				return nil, inputCursor, false
+			}
+
+			expression.Like.Insensitive = keyword == lexer.ILikeToken
+
+			return expression, currCursor, true
+		}
+	}
+
+	return nil, inputCursor, false
+}
+
+func (p *tParser) parseInExpression(
+	inputCursor uint,
+	operand *ast.TExpression,
+	not bool,
+) (*ast.TExpression, uint, bool) {
+	expression := &ast.TInExpression{A: operand, Not: not}
+
+	if p.isSubquery(inputCursor) {
+		subquery, curr, ok := p.parseSubquery(inputCursor)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		expression.Subquery = subquery
+
+		return &ast.TExpression{In: expression, Type: ast.InType}, curr, true
+	}
+
+	rightParenthToken := *lexer.RightParenthToken.AsToken()
+
+	_, curr, ok := p.parseToken(inputCursor, *lexer.LeftParenthToken.AsToken())
+	if !ok {
+		p.expect(inputCursor, `"("`)
+		return nil, inputCursor, false
+	}
+
+	list, curr, ok := p.parseExpressions(curr, []lexer.TToken{rightParenthToken})
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	if len(*list) == 0 {
+		p.expect(curr, "expression")
+		return nil, inputCursor, false
+	}
+
+	_, curr, ok = p.parseToken(curr, rightParenthToken)
+	if !ok {
+		p.expect(curr, `")"`)
+		return nil, inputCursor, false
+	}
+
+	expression.List = *list
+
+	return &ast.TExpression{In: expression, Type: ast.InType}, curr, true
+}
+
+func (p *tParser) parseBetweenExpression(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+	operand *ast.TExpression,
+	not bool,
+) (*ast.TExpression, uint, bool) {
+	expression := &ast.TBetweenExpression{A: operand, Not: not}
+
+	low, curr, ok := p.parseExpression(inputCursor, delimeters, predicatePower)
+	if !ok {
+		p.expect(inputCursor, "expression")
+		return nil, inputCursor, false
+	}
+
+	curr, ok = p.parseKeywords(curr, lexer.AndToken)
+	if !ok {
+		p.expect(curr, "AND")
+		return nil, inputCursor, false
+	}
+
+	high, curr, ok := p.parseExpression(curr, delimeters, predicatePower)
+	if !ok {
+		p.expect(curr, "expression")
+		return nil, inputCursor, false
+	}
+
+	expression.Low = low
+	expression.High = high
+
+	return &ast.TExpression{Between: expression, Type: ast.BetweenType}, curr, true
+}
+
+func (p *tParser) parseLikeExpression(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+	operand *ast.TExpression,
+	not bool,
+) (*ast.TExpression, uint, bool) {
+	expression := &ast.TLikeExpression{A: operand, Not: not}
+
+	pattern, curr, ok := p.parseExpression(inputCursor, delimeters, predicatePower)
+	if !ok {
+		p.expect(inputCursor, "expression")
+		return nil, inputCursor, false
+	}
+
+	expression.Pattern = pattern
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.EscapeToken); ok {
+		expression.Escape, curr, ok = p.parseExpression(currCursor, delimeters, predicatePower)
+		if !ok {
+			p.expect(currCursor, "expression")
+			return nil, inputCursor, false
+		}
+	}
+
+	return &ast.TExpression{Like: expression, Type: ast.LikeType}, curr, true
+}
+
+func (p *tParser) parseCaseExpression(inputCursor uint) (*ast.TExpression, uint, bool) {
+	curr, ok := p.parseKeywords(inputCursor, lexer.CaseToken)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	delimeters := []lexer.TToken{
+		*lexer.WhenToken.AsToken(),
+		*lexer.ThenToken.AsToken(),
+		*lexer.ElseToken.AsToken(),
+		*lexer.EndToken.AsToken(),
+	}
+
+	expression := &ast.TCaseExpression{}
+
+	if _, ok := p.parseKeywords(curr, lexer.WhenToken); !ok {
+		expression.Operand, curr, ok = p.parseExpression(curr, delimeters, lowestPower)
+		if !ok {
+			p.expect(curr, "expression", "WHEN")
+			return nil, inputCursor, false
+		}
+	}
+
+	for {
+		currCursor, ok := p.parseKeywords(curr, lexer.WhenToken)
+		if !ok {
+			if len(expression.Whens) == 0 {
+				p.expect(curr, "WHEN")
+				return nil, inputCursor, false
+			}
+
+			break
+		}
+
+		when := &ast.TWhenClause{}
+
+		when.Condition, currCursor, ok = p.parseExpression(currCursor, delimeters, lowestPower)
+		if !ok {
+			p.expect(currCursor, "expression")
+			return nil, inputCursor, false
+		}
+
+		currCursor, ok = p.parseKeywords(currCursor, lexer.ThenToken)
+		if !ok {
+			p.expect(currCursor, "THEN")
+			return nil, inputCursor, false
+		}
+
+		when.Result, currCursor, ok = p.parseExpression(currCursor, delimeters, lowestPower)
+		if !ok {
+			p.expect(currCursor, "expression")
+			return nil, inputCursor, false
+		}
+
+		expression.Whens = append(expression.Whens, when)
+		curr = currCursor
+	}
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.ElseToken); ok {
+		expression.Else, curr, ok = p.parseExpression(currCursor, delimeters, lowestPower)
+		if !ok {
+			p.expect(currCursor, "expression")
+			return nil, inputCursor, false
+		}
+	}
+
+	curr, ok = p.parseKeywords(curr, lexer.EndToken)
+	if !ok {
+		if expression.Else == nil {
+			p.expect(curr, "WHEN", "ELSE", "END")
+		} else {
+			p.expect(curr, "END")
+		}
+
+		return nil, inputCursor, false
+	}
+
+	return &ast.TExpression{Case: expression, Type: ast.CaseType}, curr, true
+}
+
+func (p *tParser) parseCastExpression(inputCursor uint) (*ast.TExpression, uint, bool) {
+	curr, ok := p.parseKeywords(inputCursor, lexer.CastToken)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	_, curr, ok = p.parseToken(curr, *lexer.LeftParenthToken.AsToken())
+	if !ok {
+		p.expect(curr, `"("`)
+		return nil, inputCursor, false
+	}
+
+	operand, curr, ok := p.parseExpression(curr, []lexer.TToken{*lexer.AsToken.AsToken()}, lowestPower)
+	if !ok {
+		p.expect(curr, "expression")
+		return nil, inputCursor, false
+	}
+
+	curr, ok = p.parseKeywords(curr, lexer.AsToken)
+	if !ok {
+		p.expect(curr, "AS")
+		return nil, inputCursor, false
+	}
+
+	datatype, params, curr, ok := p.parseDatatype(curr)
+	if !ok {
+		p.expect(curr, "datatype")
+		return nil, inputCursor, false
+	}
+
+	_, curr, ok = p.parseToken(curr, *lexer.RightParenthToken.AsToken())
+	if !ok {
+		p.expect(curr, `")"`)
+		return nil, inputCursor, false
+	}
+
+	return &ast.TExpression{
+		Cast: &ast.TCastExpression{
+			Operand:        operand,
+			Datatype:       *datatype,
+			DatatypeParams: params,
+		},
+		Type: ast.CastType,
+	}, curr, true
+}
+
+func (p *tParser) parseIsExpression(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+	operand *ast.TExpression,
+) (*ast.TExpression, uint, bool) {
+	curr, ok := p.parseKeywords(inputCursor, lexer.IsToken)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	expression := &ast.TIsExpression{A: operand}
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.NotToken); ok {
+		expression.Not = true
+		curr = currCursor
+	}
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.DistinctToken, lexer.FromToken); ok {
+		rhs, currCursor, ok := p.parseExpression(currCursor, delimeters, isPower)
+		if !ok {
+			p.expect(currCursor, "expression")
+			return nil, inputCursor, false
+		}
+
+		expression.B = rhs
+		expression.Distinct = true
+
+		return &ast.TExpression{Is: expression, Type: ast.IsType}, currCursor, true
+	}
+
+	for _, ttype := range []lexer.ETokenType{lexer.NullType, lexer.BooleanType} {
+		if currToken, currCursor, ok := p.parseTokenType(curr, ttype); ok {
+			expression.B = &ast.TExpression{Literal: currToken, Type: ast.LiteralType}
+
+			return &ast.TExpression{Is: expression, Type: ast.IsType}, currCursor, true
+		}
+	}
+
+	p.expect(curr, "NULL", "TRUE", "FALSE", "DISTINCT FROM")
+	return nil, inputCursor, false
+}
+
+func (p *tParser) parseExpressions(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+) (*[]*ast.TExpression, uint, bool) {
+	curr := inputCursor
+
+	commaToken := *lexer.CommaToken.AsToken()
+	expressionDelimeters := append([]lexer.TToken{commaToken}, delimeters...)
+
+	expressions := []*ast.TExpression{}
+
+	for {
+		if curr >= uint(len(p.tokens)) {
+			return nil, inputCursor, false
+		}
+
+		currToken := p.tokens[curr]
+		if isDelimeter(currToken, &delimeters) {
+			break
+		}
+
+		if len(expressions) > 0 {
+			var ok bool
+			_, curr, ok = p.parseToken(curr, commaToken)
+			if !ok {
+				p.expectTokens(curr, append([]lexer.TToken{commaToken}, delimeters...)...)
+				return nil, inputCursor, false
+			}
+		}
+
+		expression, currCursor, ok := p.parseExpression(curr, expressionDelimeters, lowestPower)
+		if !ok {
+			p.expect(curr, "expression")
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		expressions = append(expressions, expression)
+	}
+
+	return &expressions, curr, true
+}
+
+func (p *tParser) parseSelectRules(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+) ([]*ast.TSelectRule, uint, bool) {
+	curr := inputCursor
+
+	commaToken := *lexer.CommaToken.AsToken()
+	expressionDelimeters := append([]lexer.TToken{commaToken}, delimeters...)
+
+	rules := []*ast.TSelectRule{}
+
+	for {
+		if curr >= uint(len(p.tokens)) {
+			return nil, inputCursor, false
+		}
+
+		if isDelimeter(p.tokens[curr], &delimeters) {
+			break
+		}
+
+		if len(rules) > 0 {
+			var ok bool
+			_, curr, ok = p.parseToken(curr, commaToken)
+			if !ok {
+				if last := rules[len(rules)-1]; last.Alias == nil && !last.Star {
+					p.expect(curr, "AS")
+				}
+				p.expectTokens(curr, expressionDelimeters...)
+				return nil, inputCursor, false
+			}
+		}
+
+		if rule, currCursor, ok := p.parseStarRule(curr); ok {
+			rules = append(rules, rule)
+			curr = currCursor
+			continue
+		}
+
+		expression, currCursor, ok := p.parseExpression(curr, expressionDelimeters, lowestPower)
+		if !ok {
+			p.expect(curr, "expression")
+			return nil, inputCursor, false
+		}
+
+		alias, currCursor, ok := p.parseAlias(currCursor)
+		if !ok {
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		rules = append(rules, &ast.TSelectRule{Expression: expression, Alias: alias})
+	}
+
+	if len(rules) == 0 {
+		p.expect(curr, "expression")
+		return nil, inputCursor, false
+	}
+
+	return rules, curr, true
+}
+
+func (p *tParser) parseStarRule(inputCursor uint) (*ast.TSelectRule, uint, bool) {
+	asteriksToken := *lexer.AsteriksToken.AsToken()
+
+	if _, curr, ok := p.parseToken(inputCursor, asteriksToken); ok {
+		return &ast.TSelectRule{Star: true}, curr, true
+	}
+
+	table, curr, ok := p.parseTokenType(inputCursor, lexer.IdentifierType)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	_, curr, ok = p.parseToken(curr, *lexer.DotToken.AsToken())
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	_, curr, ok = p.parseToken(curr, asteriksToken)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	return &ast.TSelectRule{Star: true, Table: table}, curr, true
+}
+
+func (p *tParser) parseAlias(inputCursor uint) (*lexer.TToken, uint, bool) {
+	curr, hasAs := p.parseKeywords(inputCursor, lexer.AsToken)
+
+	alias, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+	if ok {
+		return alias, currCursor, true
+	}
+
+	if !hasAs {
+		return nil, inputCursor, true
+	}
+
+	// Any keyword may follow an explicit AS, e.g. SELECT a AS key.
+	if keyword, currCursor, ok := p.parseTokenType(curr, lexer.ReservedType); ok {
+		return &lexer.TToken{Value: keyword.Value, Type: lexer.IdentifierType, Loc: keyword.Loc}, currCursor, true
+	}
+
+	p.expect(curr, "alias")
+	return nil, inputCursor, false
+}
+
+func (p *tParser) parseKeywords(inputCursor uint, keywords ...lexer.TReservedToken) (uint, bool) {
+	curr := inputCursor
+
+	for _, keyword := range keywords {
+		var ok bool
+		_, curr, ok = p.parseToken(curr, *keyword.AsToken())
+		if !ok {
+			return inputCursor, false
+		}
+	}
+
+	return curr, true
+}
+
+func (p *tParser) parseParenthesisedExpression(
+	inputCursor uint,
+) (*ast.TExpression, uint, bool) {
+	curr := inputCursor
+	rightParenthToken := *lexer.RightParenthToken.AsToken()
+
+	_, curr, ok := p.parseToken(curr, *lexer.LeftParenthToken.AsToken())
+	if !ok {
+		p.expect(curr, `"("`)
+		return nil, inputCursor, false
+	}
+
+	expression, curr, ok := p.parseExpression(curr, []lexer.TToken{rightParenthToken}, lowestPower)
+	if !ok {
+		p.expect(curr, "expression")
+		return nil, inputCursor, false
+	}
+
+	_, curr, ok = p.parseToken(curr, rightParenthToken)
+	if !ok {
+		p.expect(curr, `")"`)
+		return nil, inputCursor, false
+	}
+
+	return expression, curr, true
+}
+
+func (p *tParser) parseIdentifierList(
+	inputCursor uint,
+) ([]lexer.TToken, uint, bool) {
+	curr := inputCursor
+	rightParenthToken := *lexer.RightParenthToken.AsToken()
+
+	_, curr, ok := p.parseToken(curr, *lexer.LeftParenthToken.AsToken())
+	if !ok {
+		p.expect(curr, `"("`)
+		return nil, inputCursor, false
+	}
+
+	identifiers := []lexer.TToken{}
+
+	for {
+		if len(identifiers) > 0 {
+			if _, currCursor, ok := p.parseToken(curr, rightParenthToken); ok {
+				return identifiers, currCursor, true
+			}
+
+			_, curr, ok = p.parseToken(curr, *lexer.CommaToken.AsToken())
+			if !ok {
+				p.expectTokens(curr, *lexer.CommaToken.AsToken(), rightParenthToken)
+				return nil, inputCursor, false
+			}
+		}
+
+		identifier, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+		if !ok {
+			p.expect(curr, "column name")
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		identifiers = append(identifiers, *identifier)
+	}
+}
+
+func (p *tParser) parseColumnMeta(
+	inputCursor uint,
+	delimeter lexer.TToken,
+) (*[]*ast.TColumnMeta, []*ast.TTableConstraint, uint, bool) {
+	curr := inputCursor
+
+	columnsMeta := []*ast.TColumnMeta{}
+	var constraints []*ast.TTableConstraint
+
+	for {
+		if curr >= uint(len(p.tokens)) {
+			return nil, nil, inputCursor, false
+		}
+
+		if currToken := p.tokens[curr]; delimeter.Equal(currToken) {
+			break
+		}
+
+		if len(columnsMeta)+len(constraints) > 0 {
+			var ok bool
+			_, curr, ok = p.parseToken(curr, *lexer.CommaToken.AsToken())
+			if !ok {
+				p.expectTokens(curr, *lexer.CommaToken.AsToken(), delimeter)
+				return nil, nil, inputCursor, false
+			}
+		}
+
+		if constraint, currCursor, ok := p.parseTableConstraint(curr); ok {
+			curr = currCursor
+			constraints = append(constraints, constraint)
+			continue
+		}
+
+		columnMeta, currCursor, ok := p.parseColumnDefinition(
+			curr,
+			[]lexer.TToken{*lexer.CommaToken.AsToken(), delimeter},
+		)
+		if !ok {
+			return nil, nil, inputCursor, false
+		}
+		curr = currCursor
+
+		columnsMeta = append(columnsMeta, columnMeta)
+	}
+
+	return &columnsMeta, constraints, curr, true
+}
+
+func (p *tParser) parseTableConstraint(
+	inputCursor uint,
+) (*ast.TTableConstraint, uint, bool) {
+	curr := inputCursor
+	constraint := &ast.TTableConstraint{}
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.CheckToken); ok {
+		check, currCursor, ok := p.parseParenthesisedExpression(currCursor)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		constraint.Type = ast.CheckConstraint
+		constraint.Check = check
+
+		return constraint, currCursor, true
+	}
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.PrimaryToken, lexer.KeyToken); ok {
+		constraint.Type = ast.PrimaryKeyConstraint
+		curr = currCursor
+	} else if currCursor, ok := p.parseKeywords(curr, lexer.UniqueToken); ok {
+		constraint.Type = ast.UniqueConstraint
+		curr = currCursor
+	} else {
+		return nil, inputCursor, false
+	}
+
+	columns, curr, ok := p.parseIdentifierList(curr)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	constraint.Columns = columns
+
+	return constraint, curr, true
+}
+
+func (p *tParser) parseDatatype(
+	inputCursor uint,
+) (*lexer.TToken, []lexer.TToken, uint, bool) {
+	curr := inputCursor
+
+	var datatype *lexer.TToken
+	for _, candidate := range datatypes {
+		if token, currCursor, ok := p.parseToken(curr, *candidate.AsToken()); ok {
+			datatype = token
+			curr = currCursor
+			break
+		}
+	}
+
+	if datatype == nil {
+		return nil, nil, inputCursor, false
+	}
+
+	switch lexer.TReservedToken(datatype.Value) {
+	case lexer.DoubleToken:
+		curr, _ = p.parseKeywords(curr, lexer.PrecisionToken)
+	case lexer.NumericToken, lexer.DecimalToken, lexer.VarcharToken:
+		if _, currCursor, ok := p.parseToken(curr, *lexer.LeftParenthToken.AsToken()); ok {
+			params, currCursor, ok := p.parseExpressions(currCursor, []lexer.TToken{*lexer.RightParenthToken.AsToken()})
+			if !ok {
+				return nil, nil, inputCursor, false
+			}
+
+			_, currCursor, _ = p.parseToken(currCursor, *lexer.RightParenthToken.AsToken())
+
+			maxParams := 2
+			if datatype.Value == string(lexer.VarcharToken) {
+				maxParams = 1
+			}
+
+			if len(*params) == 0 || len(*params) > maxParams {
+				p.reject(curr, "Unexpected number of datatype parameters")
+				return nil, nil, inputCursor, false
+			}
+
+			datatypeParams := []lexer.TToken{}
+			for _, param := range *params {
+				if param.Type != ast.LiteralType || param.Literal.Type != lexer.NumericType {
+					p.reject(curr, "Expected integer datatype parameters")
+					return nil, nil, inputCursor, false
+				}
+
+				datatypeParams = append(datatypeParams, *param.Literal)
+			}
+
+			return datatype, datatypeParams, currCursor, true
+		}
+	}
+
+	return datatype, nil, curr, true
+}
+
+func (p *tParser) parseColumnDefinition(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+) (*ast.TColumnMeta, uint, bool) {
+	curr := inputCursor
+
+	columnName, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+	if !ok {
+		p.expect(curr, "column name")
+		return nil, inputCursor, false
+	}
+	curr = currCursor
+
+	columnType, columnTypeParams, currCursor, ok := p.parseDatatype(curr)
+	if !ok {
+		p.expect(curr, "datatype")
+		return nil, inputCursor, false
+	}
+	curr = currCursor
+
+	columnMeta := &ast.TColumnMeta{
+		Name:           *columnName,
+		Datatype:       *columnType,
+		DatatypeParams: columnTypeParams,
+	}
+
+	seen := map[string]bool{}
+
+	for {
+		option := ""
+		optionCursor := curr
+
+		if currCursor, ok := p.parseKeywords(curr, lexer.PrimaryToken, lexer.KeyToken); ok {
+			option = "PRIMARY KEY"
+			columnMeta.PrimaryKey = true
+			curr = currCursor
+		} else if currCursor, ok := p.parseKeywords(curr, lexer.NotToken, lexer.NullToken); ok {
+			option = "NOT NULL"
+			columnMeta.NotNull = true
+			curr = currCursor
+		} else if currCursor, ok := p.parseKeywords(curr, lexer.NullToken); ok {
+			option = "NULL"
+			curr = currCursor
+		} else if currCursor, ok := p.parseKeywords(curr, lexer.UniqueToken); ok {
+			option = "UNIQUE"
+			columnMeta.Unique = true
+			curr = currCursor
+		} else if currCursor, ok := p.parseKeywords(curr, lexer.DefaultToken); ok {
+			value, currCursor, ok := p.parseExpression(currCursor, delimeters, lowestPower)
+			if !ok {
+				p.expect(currCursor, "expression")
+				return nil, inputCursor, false
+			}
+
+			option = "DEFAULT"
+			columnMeta.Default = value
+			curr = currCursor
+		} else if currCursor, ok := p.parseKeywords(curr, lexer.CheckToken); ok {
+			check, currCursor, ok := p.parseParenthesisedExpression(currCursor)
+			if !ok {
+				return nil, inputCursor, false
+			}
+
+			// A column may carry any number of CHECK constraints.
+			columnMeta.Checks = append(columnMeta.Checks, check)
+			curr = currCursor
+			continue
+		} else {
+			break
+		}
+
+		if seen[option] {
+			p.reject(optionCursor, fmt.Sprintf("Duplicate %s in definition of column %q", option, columnName.Value))
+			return nil, inputCursor, false
+		}
+		seen[option] = true
+
+		if seen["NULL"] && (seen["NOT NULL"] || seen["PRIMARY KEY"]) {
+			p.reject(optionCursor, fmt.Sprintf("Conflicting NULL and NOT NULL in definition of column %q", columnName.Value))
+			return nil, inputCursor, false
+		}
+	}
+
+	return columnMeta, curr, true
+}
+
+func (p *tParser) parseCreateTableStatement(
+	inputCursor uint,
+	delimeter lexer.TToken,
+) (*ast.TCreateTableStatement, uint, bool) {
+	curr := inputCursor
+	ok := false
+
+	_, curr, ok = p.parseToken(curr, *lexer.CreateToken.AsToken())
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	_, curr, ok = p.parseToken(curr, *lexer.TableToken.AsToken())
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	tableName, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+	if !ok {
+		p.expect(curr, "table name")
+		return nil, inputCursor, false
+	}
+	curr = currCursor
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.AsToken); ok {
+		selectStatement, currCursor, ok := p.parseSelectStatement(currCursor, delimeter)
+		if !ok {
+			p.expect(currCursor, "SELECT")
+			return nil, inputCursor, false
+		}
+
+		return &ast.TCreateTableStatement{
+			TableName: *tableName,
+			As:        selectStatement,
+		}, currCursor, true
+	}
+
+	_, curr, ok = p.parseToken(curr, *lexer.LeftParenthToken.AsToken())
+	if !ok {
+		p.expect(curr, `"("`, "AS")
+		return nil, inputCursor, false
+	}
+
+	columnsDesc, constraints, currCursor, ok := p.parseColumnMeta(curr, *lexer.RightParenthToken.AsToken())
+	if !ok {
+		return nil, inputCursor, false
+	}
+	curr = currCursor
+
+	_, curr, ok = p.parseToken(curr, *lexer.RightParenthToken.AsToken())
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	return &ast.TCreateTableStatement{
+		TableName:   *tableName,
+		Columns:     columnsDesc,
+		Constraints: constraints,
+	}, curr, true
+}
+
+var setOperations = map[lexer.TReservedToken]ast.ESetOperation{
+	lexer.UnionToken:     ast.UnionOperation,
+	lexer.IntersectToken: ast.IntersectOperation,
+	lexer.ExceptToken:    ast.ExceptOperation,
+}
+
+func (p *tParser) parseSelectStatement(
+	inputCursor uint,
+	delimeter lexer.TToken,
+) (*ast.TSelectStatement, uint, bool) {
+	resStatement, curr, ok := p.parseCompoundSelect(inputCursor, delimeter, false)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	limitToken := *lexer.LimitToken.AsToken()
+	offsetToken := *lexer.OffsetToken.AsToken()
+	pagingDelimeters := []lexer.TToken{limitToken, offsetToken, delimeter}
+
+	resStatement.OrderBy, curr, ok = p.parseOrderBy(curr, pagingDelimeters)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	for {
+		if resStatement.Limit == nil {
+			limit, currCursor, ok := p.parseKeywordExpression(curr, lexer.LimitToken, pagingDelimeters)
+			if !ok {
+				return nil, inputCursor, false
+			}
+
+			if limit != nil {
+				resStatement.Limit, curr = limit, currCursor
+				continue
+			}
+		}
+
+		if resStatement.Offset == nil {
+			offset, currCursor, ok := p.parseKeywordExpression(curr, lexer.OffsetToken, pagingDelimeters)
+			if !ok {
+				return nil, inputCursor, false
+			}
+
+			if offset != nil {
+				resStatement.Offset, curr = offset, currCursor
+				continue
+			}
+		}
+
+		break
+	}
+
+	return resStatement, curr, true
+}
+
+// parseCompoundSelect parses queries joined by set operations, left to right
+// and with INTERSECT binding tighter than UNION and EXCEPT.
+func (p *tParser) parseCompoundSelect(
+	inputCursor uint,
+	delimeter lexer.TToken,
+	intersection bool,
+) (*ast.TSelectStatement, uint, bool) {
+	parseOperand := func(cursor uint) (*ast.TSelectStatement, uint, bool) {
+		if intersection {
+			return p.parseSelectCore(cursor, delimeter)
+		}
+
+		return p.parseCompoundSelect(cursor, delimeter, true)
+	}
+
+	resStatement, curr, ok := parseOperand(inputCursor)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	operators := []lexer.TReservedToken{lexer.UnionToken, lexer.ExceptToken}
+	if intersection {
+		operators = []lexer.TReservedToken{lexer.IntersectToken}
+	}
+
+	for {
+		var operator lexer.TReservedToken
+
+		currCursor := curr
+		for _, candidate := range operators {
+			if currCursor, ok = p.parseKeywords(curr, candidate); ok {
+				operator = candidate
+				break
+			}
+		}
+
+		if operator == "" {
+			break
+		}
+
+		compound := &ast.TCompoundSelect{Operation: setOperations[operator], Left: resStatement}
+
+		if currCursor, compound.All = p.parseKeywords(currCursor, lexer.AllToken); !compound.All {
+			currCursor, _ = p.parseKeywords(currCursor, lexer.DistinctToken)
+		}
+
+		compound.Right, currCursor, ok = parseOperand(currCursor)
+		if !ok {
+			p.expect(currCursor, "SELECT")
+			return nil, inputCursor, false
+		}
+
+		resStatement = &ast.TSelectStatement{Compound: compound}
+		curr = currCursor
+	}
+
+	return resStatement, curr, true
+}
+
+// parseSelectCore parses a single SELECT up to its HAVING clause.
+func (p *tParser) parseSelectCore(
+	inputCursor uint,
+	delimeter lexer.TToken,
+) (*ast.TSelectStatement, uint, bool) {
+	curr := inputCursor
+	ok := false
+
+	_, curr, ok = p.parseToken(curr, *lexer.SelectToken.AsToken())
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	resStatement := ast.TSelectStatement{}
+
+	fromToken := *lexer.FromToken.AsToken()
+	whereToken := *lexer.WhereToken.AsToken()
+	groupToken := *lexer.GroupToken.AsToken()
+	havingToken := *lexer.HavingToken.AsToken()
+	orderToken := *lexer.OrderToken.AsToken()
+	limitToken := *lexer.LimitToken.AsToken()
+	offsetToken := *lexer.OffsetToken.AsToken()
+	unionToken := *lexer.UnionToken.AsToken()
+	intersectToken := *lexer.IntersectToken.AsToken()
+	exceptToken := *lexer.ExceptToken.AsToken()
+
+	endDelimeters := []lexer.TToken{
+		orderToken,
+		limitToken,
+		offsetToken,
+		unionToken,
+		intersectToken,
+		exceptToken,
+		delimeter,
+	}
+
+	rules, curr, ok := p.parseSelectRules(
+		curr,
+		append([]lexer.TToken{fromToken, whereToken, groupToken, havingToken}, endDelimeters...),
+	)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	resStatement.Rules = rules
+
+	_, curr, ok = p.parseToken(curr, fromToken)
+	if ok {
+		resStatement.From, curr, ok = p.parseTableExpression(
+			curr,
+			append([]lexer.TToken{whereToken, groupToken, havingToken}, endDelimeters...),
+		)
+		if !ok {
+			return nil, inputCursor, false
+		}
+	}
+
+	resStatement.Where, curr, ok = p.parseWhere(curr, delimeter)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	resStatement.GroupBy, curr, ok = p.parseGroupBy(
+		curr,
+		append([]lexer.TToken{havingToken}, endDelimeters...),
+	)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	resStatement.Having, curr, ok = p.parseKeywordExpression(curr, lexer.HavingToken, endDelimeters)
+	if !ok {
+		return nil, inputCursor, false
+	}
+
+	return &resStatement, curr, true
+}
+
+func (p *tParser) parseGroupBy(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+) ([]*ast.TExpression, uint, bool) {
+	curr, ok := p.parseKeywords(inputCursor, lexer.GroupToken)
+	if !ok {
+		return nil, inputCursor, true
+	}
+
+	curr, ok = p.parseKeywords(curr, lexer.ByToken)
+	if !ok {
+		p.expect(curr, "BY")
+		return nil, inputCursor, false
+	}
+
+	commaToken := *lexer.CommaToken.AsToken()
+	expressionDelimeters := append([]lexer.TToken{commaToken}, delimeters...)
+
+	groupBy := []*ast.TExpression{}
+
+	for {
+		expression, currCursor, ok := p.parseExpression(curr, expressionDelimeters, lowestPower)
+		if !ok {
+			p.expect(curr, "expression")
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		groupBy = append(groupBy, expression)
+
+		_, currCursor, ok = p.parseToken(curr, commaToken)
+		if !ok {
+			break
+		}
+		curr = currCursor
+	}
+
+	return groupBy, curr, true
+}
+
+func (p *tParser) parseOrderBy(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+) ([]*ast.TOrderTerm, uint, bool) {
+	curr, ok := p.parseKeywords(inputCursor, lexer.OrderToken)
+	if !ok {
+		return nil, inputCursor, true
+	}
+
+	curr, ok = p.parseKeywords(curr, lexer.ByToken)
+	if !ok {
+		p.expect(curr, "BY")
+		return nil, inputCursor, false
+	}
+
+	commaToken := *lexer.CommaToken.AsToken()
+	expressionDelimeters := append([]lexer.TToken{commaToken}, delimeters...)
+
+	terms := []*ast.TOrderTerm{}
+
+	for {
+		expression, currCursor, ok := p.parseExpression(curr, expressionDelimeters, lowestPower)
+		if !ok {
+			p.expect(curr, "expression")
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		term := &ast.TOrderTerm{Expression: expression}
+
+		if currCursor, ok := p.parseKeywords(curr, lexer.DescToken); ok {
+			term.Descending = true
+			curr = currCursor
+		} else if currCursor, ok := p.parseKeywords(curr, lexer.AscToken); ok {
+			curr = currCursor
+		}
+
+		if currCursor, ok := p.parseKeywords(curr, lexer.NullsToken); ok {
+			curr = currCursor
+
+			if currCursor, ok := p.parseKeywords(curr, lexer.FirstToken); ok {
+				term.Nulls = ast.NullsFirst
+				curr = currCursor
+			} else if currCursor, ok := p.parseKeywords(curr, lexer.LastToken); ok {
+				term.Nulls = ast.NullsLast
+				curr = currCursor
+			} else {
+				p.expect(curr, "FIRST", "LAST")
+				return nil, inputCursor, false
+			}
+		}
+
+		terms = append(terms, term)
 
-	if cursor < uint(len(tokens)) {
-		curr = tokens[cursor]
-	} else {
-		curr = tokens[cursor-1]
+		_, currCursor, ok = p.parseToken(curr, commaToken)
+		if !ok {
+			break
+		}
+		curr = currCursor
 	}
 
-	fmt.Printf("[%d, %d]: %s, got: %s\n", curr.Loc.Line, curr.Loc.Column, message, curr.Value)
+	return terms, curr, true
 }
 
-func isDelimeter(candidate *lexer.TToken, delimeters *[]lexer.TToken) bool {
-	for _, delimeter := range *delimeters {
-		if delimeter.Equal(candidate) {
-			return true
-		}
+func (p *tParser) parseKeywordExpression(
+	inputCursor uint,
+	keyword lexer.TReservedToken,
+	delimeters []lexer.TToken,
+) (*ast.TExpression, uint, bool) {
+	curr, ok := p.parseKeywords(inputCursor, keyword)
+	if !ok {
+		return nil, inputCursor, true
 	}
 
-	return false
-}
+	expression, curr, ok := p.parseExpression(curr, delimeters, lowestPower)
+	if !ok {
+		p.expect(curr, "expression")
+		return nil, inputCursor, false
+	}
 
-func parseTokenType(
-	tokens []*lexer.TToken,
-	initialCursor uint,
-	ttype lexer.ETokenType,
-) (*lexer.TToken, uint, bool) {
-	cursor := initialCursor
+	return expression, curr, true
+}
 
-	if cursor >= uint(len(tokens)) {
-		return nil, initialCursor, false
+func (p *tParser) parseTableExpression(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+) (*ast.TTableExpression, uint, bool) {
+	left, curr, ok := p.parseTableReference(inputCursor)
+	if !ok {
+		return nil, inputCursor, false
 	}
 
-	if currToken := tokens[cursor]; currToken.Type == ttype {
-		return currToken, cursor + 1, true
+	for {
+		join := &ast.TJoin{Left: left}
+
+		if _, currCursor, ok := p.parseToken(curr, *lexer.CommaToken.AsToken()); ok {
+			join.Type = ast.CrossJoin
+			curr = currCursor
+		} else if join.Type, curr, ok = p.parseJoinType(curr); !ok {
+			break
+		}
+
+		join.Right, curr, ok = p.parseTableReference(curr)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		if join.Type != ast.CrossJoin {
+			if currCursor, ok := p.parseKeywords(curr, lexer.OnToken); ok {
+				join.On, curr, ok = p.parseExpression(currCursor, delimeters, lowestPower)
+				if !ok {
+					p.expect(currCursor, "expression")
+					return nil, inputCursor, false
+				}
+			} else if currCursor, ok := p.parseKeywords(curr, lexer.UsingToken); ok {
+				join.Using, curr, ok = p.parseIdentifierList(currCursor)
+				if !ok {
+					return nil, inputCursor, false
+				}
+			} else {
+				p.expect(curr, "ON", "USING")
+				return nil, inputCursor, false
+			}
+		}
+
+		left = &ast.TTableExpression{Join: join, Type: ast.JoinExpressionType}
 	}
 
-	return nil, initialCursor, false
+	return left, curr, true
 }
 
-func parseToken(tokens []*lexer.TToken, inputCursor uint, candToken lexer.TToken) (*lexer.TToken, uint, bool) {
-	curr := inputCursor
+func (p *tParser) parseTableReference(inputCursor uint) (*ast.TTableExpression, uint, bool) {
+	if p.isSubquery(inputCursor) {
+		subquery, curr, ok := p.parseSubquery(inputCursor)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		alias, curr, ok := p.parseAlias(curr)
+		if !ok {
+			return nil, inputCursor, false
+		}
+
+		return &ast.TTableExpression{
+			Derived: &ast.TDerivedTable{Select: subquery, Alias: alias},
+			Type:    ast.DerivedTableType,
+		}, curr, true
+	}
 
-	if curr >= uint(len(tokens)) {
+	table, curr, ok := p.parseTokenType(inputCursor, lexer.IdentifierType)
+	if !ok {
+		p.expect(inputCursor, "table name")
 		return nil, inputCursor, false
 	}
 
-	if currToken := tokens[curr]; candToken.Equal(currToken) {
-		return currToken, curr + 1, true
+	alias, curr, ok := p.parseAlias(curr)
+	if !ok {
+		return nil, inputCursor, false
 	}
 
-	return nil, inputCursor, false
+	return &ast.TTableExpression{
+		Table: &ast.TTableReference{Table: *table, Alias: alias},
+		Type:  ast.TableReferenceType,
+	}, curr, true
 }
 
-func parseExpression(
-	tokens []*lexer.TToken,
-	inputCursor uint,
-	_ lexer.TToken, /* delimeter token in future */
-) (*ast.TExpression, uint, bool) {
+func (p *tParser) parseJoinType(inputCursor uint) (ast.EJoinType, uint, bool) {
+	joinTypes := []struct {
+		keyword  lexer.TReservedToken
+		joinType ast.EJoinType
+	}{
+		{lexer.InnerToken, ast.InnerJoin},
+		{lexer.LeftToken, ast.LeftJoin},
+		{lexer.RightToken, ast.RightJoin},
+		{lexer.FullToken, ast.FullJoin},
+		{lexer.CrossToken, ast.CrossJoin},
+	}
+
 	curr := inputCursor
+	joinType := ast.InnerJoin
 
-	types := []lexer.ETokenType{lexer.IdentifierType, lexer.NumericType, lexer.StringType}
+	for _, candidate := range joinTypes {
+		if currCursor, ok := p.parseKeywords(curr, candidate.keyword); ok {
+			joinType = candidate.joinType
+			curr = currCursor
 
-	for _, ttype := range types {
-		if currToken, currCursor, ok := parseTokenType(tokens, curr, ttype); ok {
-			return &ast.TExpression{
-				Literal: currToken,
-				Type:    ast.LiteralType,
-			}, currCursor, true
+			if joinType == ast.LeftJoin || joinType == ast.RightJoin || joinType == ast.FullJoin {
+				curr, _ = p.parseKeywords(curr, lexer.OuterToken)
+			}
+
+			break
 		}
 	}
 
-	return nil, inputCursor, false
+	curr, ok := p.parseKeywords(curr, lexer.JoinToken)
+	if !ok {
+		if curr != inputCursor {
+			p.expect(curr, "JOIN")
+		}
+
+		return 0, inputCursor, false
+	}
+
+	return joinType, curr, true
 }
 
-func parseExpressions(
-	tokens []*lexer.TToken,
+func (p *tParser) parseInsertStatement(
 	inputCursor uint,
-	delimeters []lexer.TToken,
-) (*[]*ast.TExpression, uint, bool) {
+	delimeter lexer.TToken,
+) (*ast.TInsertStatement, uint, bool) {
 	curr := inputCursor
+	ok := false
 
-	expressions := []*ast.TExpression{}
+	_, curr, ok = p.parseToken(curr, *lexer.InsertToken.AsToken())
+	if !ok {
+		return nil, inputCursor, ok
+	}
 
-outer:
-	for {
-		if curr >= uint(len(tokens)) {
+	_, curr, ok = p.parseToken(curr, *lexer.IntoToken.AsToken())
+	if !ok {
+		return nil, inputCursor, ok
+	}
+
+	tableName, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+	if !ok {
+		p.expect(curr, "table name")
+		return nil, inputCursor, ok
+	}
+	curr = currCursor
+
+	resStatement := ast.TInsertStatement{Table: *tableName}
+
+	if _, _, ok := p.parseToken(curr, *lexer.LeftParenthToken.AsToken()); ok {
+		columns, currCursor, ok := p.parseIdentifierList(curr)
+		if !ok {
 			return nil, inputCursor, false
 		}
 
-		currToken := tokens[curr]
-		if isDelimeter(currToken, &delimeters) {
-			break outer
+		resStatement.Columns = columns
+		curr = currCursor
+	}
+
+	if selectStatement, currCursor, ok := p.parseSelectStatement(curr, delimeter); ok {
+		resStatement.Select = selectStatement
+		return &resStatement, currCursor, true
+	}
+
+	_, curr, ok = p.parseToken(curr, *lexer.ValuesToken.AsToken())
+	if !ok {
+		p.expect(curr, "VALUES", "SELECT")
+		return nil, inputCursor, ok
+	}
+
+	for {
+		_, curr, ok = p.parseToken(curr, *lexer.LeftParenthToken.AsToken())
+		if !ok {
+			p.expect(curr, `"("`)
+			return nil, inputCursor, ok
 		}
 
-		if len(expressions) > 0 {
-			if _, curr, ok := parseToken(tokens, curr, *lexer.CommaToken.AsToken()); !ok {
-				logInfo(tokens, curr, "Maybe you missed comma")
-				return nil, inputCursor, false
-			}
-			curr++
+		values, currCursor, ok := p.parseExpressions(curr, []lexer.TToken{*lexer.RightParenthToken.AsToken()})
+		if !ok {
+			p.expect(curr, "expression")
+			return nil, inputCursor, ok
 		}
+		curr = currCursor
 
-		expression, currCursor, ok := parseExpression(tokens, curr, *lexer.CommaToken.AsToken())
+		_, curr, ok = p.parseToken(curr, *lexer.RightParenthToken.AsToken())
 		if !ok {
-			logInfo(tokens, curr, "Expected expression")
-			return nil, inputCursor, false
+			p.expect(curr, `")"`)
+			return nil, inputCursor, ok
 		}
 
-		expressions = append(expressions, expression)
+		resStatement.Values = append(resStatement.Values, *values)
 
+		_, currCursor, ok = p.parseToken(curr, *lexer.CommaToken.AsToken())
+		if !ok {
+			break
+		}
 		curr = currCursor
 	}
 
-	return &expressions, curr, true
+	return &resStatement, curr, true
 }
 
-func parseColumnMeta(
-	tokens []*lexer.TToken,
+func (p *tParser) parseWhere(
 	inputCursor uint,
 	delimeter lexer.TToken,
-) (*[]*ast.TColumnMeta, uint, bool) {
+) (*ast.TExpression, uint, bool) {
 	curr := inputCursor
 
-	columnsMeta := []*ast.TColumnMeta{}
+	_, curr, ok := p.parseToken(curr, *lexer.WhereToken.AsToken())
+	if !ok {
+		return nil, inputCursor, true
+	}
 
-	for {
-		if curr >= uint(len(tokens)) {
-			return nil, inputCursor, false
-		}
+	where, curr, ok := p.parseExpression(curr, []lexer.TToken{delimeter}, lowestPower)
+	if !ok {
+		p.expect(curr, "expression")
+		return nil, inputCursor, false
+	}
 
-		if currToken := tokens[curr]; delimeter.Equal(currToken) {
-			break
-		}
+	return where, curr, true
+}
+
+func (p *tParser) parseAssignments(
+	inputCursor uint,
+	delimeters []lexer.TToken,
+) ([]*ast.TAssignment, uint, bool) {
+	curr := inputCursor
+
+	commaToken := *lexer.CommaToken.AsToken()
+	expressionDelimeters := append([]lexer.TToken{commaToken}, delimeters...)
+
+	assignments := []*ast.TAssignment{}
 
-		if len(columnsMeta) > 0 {
+	for {
+		if len(assignments) > 0 {
 			var ok bool
-			_, curr, ok = parseToken(tokens, curr, *lexer.CommaToken.AsToken())
+			_, curr, ok = p.parseToken(curr, commaToken)
 			if !ok {
-				logInfo(tokens, curr, "Expected comma")
-				return nil, inputCursor, false
+				break
 			}
 		}
 
-		columnName, currCursor, ok := parseTokenType(tokens, curr, lexer.IdentifierType)
+		column, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
 		if !ok {
-			logInfo(tokens, curr, "Expected column name")
+			p.expect(curr, "column name")
 			return nil, inputCursor, false
 		}
 		curr = currCursor
 
-		columnType, currCursor, ok := parseTokenType(tokens, curr, lexer.ReservedType)
+		_, curr, ok = p.parseToken(curr, *lexer.EqualToken.AsToken())
+		if !ok {
+			p.expect(curr, `"="`)
+			return nil, inputCursor, false
+		}
+
+		value, currCursor, ok := p.parseExpression(curr, expressionDelimeters, lowestPower)
 		if !ok {
-			logInfo(tokens, curr, "Expected column datatype definition")
+			p.expect(curr, "expression")
 			return nil, inputCursor, false
 		}
 		curr = currCursor
 
-		columnsMeta = append(
-			columnsMeta,
-			&ast.TColumnMeta{Name: *columnName, Datatype: *columnType},
-		)
+		assignments = append(assignments, &ast.TAssignment{Column: *column, Value: value})
 	}
 
-	return &columnsMeta, curr, true
+	return assignments, curr, true
 }
 
-func parseCreateTableStatement(
-	tokens []*lexer.TToken,
+func (p *tParser) parseUpdateStatement(
 	inputCursor uint,
 	delimeter lexer.TToken,
-) (*ast.TCreateTableStatement, uint, bool) {
+) (*ast.TUpdateStatement, uint, bool) {
 	curr := inputCursor
 	ok := false
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.CreateToken.AsToken())
+	_, curr, ok = p.parseToken(curr, *lexer.UpdateToken.AsToken())
 	if !ok {
 		return nil, inputCursor, false
 	}
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.TableToken.AsToken())
-	if !ok {
-		return nil, inputCursor, false
-	}
-
-	tableName, currCursor, ok := parseTokenType(tokens, curr, lexer.IdentifierType)
+	tableName, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
 	if !ok {
+		p.expect(curr, "table name")
 		return nil, inputCursor, false
 	}
 	curr = currCursor
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.LeftParenthToken.AsToken())
+	_, curr, ok = p.parseToken(curr, *lexer.SetToken.AsToken())
 	if !ok {
+		p.expect(curr, "SET")
 		return nil, inputCursor, false
 	}
 
-	columnsDesc, currCursor, ok := parseColumnMeta(tokens, curr, *lexer.RightParenthToken.AsToken())
+	whereToken := *lexer.WhereToken.AsToken()
+
+	assignments, curr, ok := p.parseAssignments(curr, []lexer.TToken{whereToken, delimeter})
 	if !ok {
 		return nil, inputCursor, false
 	}
-	curr = currCursor
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.RightParenthToken.AsToken())
+	where, curr, ok := p.parseWhere(curr, delimeter)
 	if !ok {
 		return nil, inputCursor, false
 	}
 
-	return &ast.TCreateTableStatement{
-		TableName: *tableName,
-		Columns:   columnsDesc,
+	return &ast.TUpdateStatement{
+		Table:       *tableName,
+		Assignments: assignments,
+		Where:       where,
 	}, curr, true
 }
 
-func parseSelectStatement(
-	tokens []*lexer.TToken,
+func (p *tParser) parseDeleteStatement(
 	inputCursor uint,
 	delimeter lexer.TToken,
-) (*ast.TSelectStatement, uint, bool) {
+) (*ast.TDeleteStatement, uint, bool) {
 	curr := inputCursor
 	ok := false
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.SelectToken.AsToken())
+	_, curr, ok = p.parseToken(curr, *lexer.DeleteToken.AsToken())
 	if !ok {
 		return nil, inputCursor, false
 	}
 
-	resStatement := ast.TSelectStatement{}
-
-	fromToken := *lexer.FromToken.AsToken()
-	expressions, curr, ok := parseExpressions(tokens, curr, []lexer.TToken{fromToken, delimeter})
+	_, curr, ok = p.parseToken(curr, *lexer.FromToken.AsToken())
 	if !ok {
+		p.expect(curr, "FROM")
 		return nil, inputCursor, false
 	}
 
-	resStatement.Rules = *expressions
-
-	_, curr, ok = parseToken(tokens, curr, fromToken)
-	if ok {
-		from, currCursor, ok := parseTokenType(tokens, curr, lexer.IdentifierType)
-		if !ok {
-			logInfo(tokens, curr, "Expected FROM statement")
-			return nil, inputCursor, false
-		}
+	tableName, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+	if !ok {
+		p.expect(curr, "table name")
+		return nil, inputCursor, false
+	}
+	curr = currCursor
 
-		resStatement.From = *from
-		curr = currCursor
+	where, curr, ok := p.parseWhere(curr, delimeter)
+	if !ok {
+		return nil, inputCursor, false
 	}
 
-	return &resStatement, curr, true
+	return &ast.TDeleteStatement{
+		Table: *tableName,
+		Where: where,
+	}, curr, true
 }
 
-func parseInsertStatement(
-	tokens []*lexer.TToken,
+func (p *tParser) parseDropTableStatement(
 	inputCursor uint,
 	_ lexer.TToken,
-) (*ast.TInsertStatement, uint, bool) {
+) (*ast.TDropTableStatement, uint, bool) {
 	curr := inputCursor
 	ok := false
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.InsertToken.AsToken())
+	_, curr, ok = p.parseToken(curr, *lexer.DropToken.AsToken())
 	if !ok {
-		return nil, inputCursor, ok
+		return nil, inputCursor, false
 	}
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.IntoToken.AsToken())
+	_, curr, ok = p.parseToken(curr, *lexer.TableToken.AsToken())
 	if !ok {
-		return nil, inputCursor, ok
+		return nil, inputCursor, false
+	}
+
+	resStatement := ast.TDropTableStatement{}
+
+	if currCursor, ok := p.parseKeywords(curr, lexer.IfToken, lexer.ExistsToken); ok {
+		resStatement.IfExists = true
+		curr = currCursor
 	}
 
-	tableName, currCursor, ok := parseTokenType(tokens, curr, lexer.IdentifierType)
+	tableName, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
 	if !ok {
-		logInfo(tokens, curr, "Expected table name")
-		return nil, inputCursor, ok
+		p.expect(curr, "table name")
+		return nil, inputCursor, false
 	}
 	curr = currCursor
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.ValuesToken.AsToken())
+	resStatement.TableName = *tableName
+
+	return &resStatement, curr, true
+}
+
+func (p *tParser) parseAlterTableStatement(
+	inputCursor uint,
+	delimeter lexer.TToken,
+) (*ast.TAlterTableStatement, uint, bool) {
+	curr := inputCursor
+	ok := false
+
+	_, curr, ok = p.parseToken(curr, *lexer.AlterToken.AsToken())
 	if !ok {
-		logInfo(tokens, curr, "Expected VALUES statement")
-		return nil, inputCursor, ok
+		return nil, inputCursor, false
 	}
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.LeftParenthToken.AsToken())
+	_, curr, ok = p.parseToken(curr, *lexer.TableToken.AsToken())
 	if !ok {
-		logInfo(tokens, curr, "Expected expressions group opening (maybe you forgot opening parenthesis)")
-		return nil, inputCursor, ok
+		return nil, inputCursor, false
 	}
 
-	values, currCursor, ok := parseExpressions(tokens, curr, []lexer.TToken{*lexer.RightParenthToken.AsToken()})
+	tableName, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
 	if !ok {
-		logInfo(tokens, curr, "Expected values")
-		return nil, inputCursor, ok
+		p.expect(curr, "table name")
+		return nil, inputCursor, false
 	}
 	curr = currCursor
 
-	_, curr, ok = parseToken(tokens, curr, *lexer.RightParenthToken.AsToken())
-	if !ok {
-		logInfo(tokens, curr, "Expression way never closed")
-		return nil, inputCursor, ok
+	if curr >= uint(len(p.tokens)) {
+		return nil, inputCursor, false
+	}
+
+	resStatement := ast.TAlterTableStatement{TableName: *tableName}
+	columnToken := *lexer.ColumnToken.AsToken()
+	toToken := *lexer.ToToken.AsToken()
+
+	switch {
+	case p.tokens[curr].Equal(lexer.AddToken.AsToken()):
+		_, curr, _ = p.parseToken(curr+1, columnToken)
+
+		column, currCursor, ok := p.parseColumnDefinition(curr, []lexer.TToken{delimeter})
+		if !ok {
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		resStatement.Action = ast.AddColumnAction
+		resStatement.Column = column
+	case p.tokens[curr].Equal(lexer.DropToken.AsToken()):
+		_, curr, _ = p.parseToken(curr+1, columnToken)
+
+		target, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+		if !ok {
+			p.expect(curr, "column name")
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		resStatement.Action = ast.DropColumnAction
+		resStatement.Target = *target
+	case p.tokens[curr].Equal(lexer.RenameToken.AsToken()):
+		curr++
+		resStatement.Action = ast.RenameTableAction
+
+		if _, currCursor, ok := p.parseToken(curr, toToken); !ok {
+			_, curr, _ = p.parseToken(curr, columnToken)
+
+			target, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+			if !ok {
+				p.expect(curr, "column name")
+				return nil, inputCursor, false
+			}
+
+			_, curr, ok = p.parseToken(currCursor, toToken)
+			if !ok {
+				p.expect(currCursor, "TO")
+				return nil, inputCursor, false
+			}
+
+			resStatement.Action = ast.RenameColumnAction
+			resStatement.Target = *target
+		} else {
+			curr = currCursor
+		}
+
+		newName, currCursor, ok := p.parseTokenType(curr, lexer.IdentifierType)
+		if !ok {
+			p.expect(curr, "name")
+			return nil, inputCursor, false
+		}
+		curr = currCursor
+
+		resStatement.NewName = *newName
+	default:
+		p.expect(curr, "ADD", "DROP", "RENAME")
+		return nil, inputCursor, false
 	}
 
-	return &ast.TInsertStatement{
-		Table:  *tableName,
-		Values: values,
-	}, curr, ok
+	return &resStatement, curr, true
 }
 
-func parseStatement(
-	tokens []*lexer.TToken,
+func (p *tParser) parseStatement(
 	inputCursor uint,
 	delimeter lexer.TToken,
 ) (*ast.TStatement, uint, bool) {
 	curr := inputCursor
 	semicolonToken := lexer.SemicolonToken.AsToken()
 
-	if selectStatement, currCursor, ok := parseSelectStatement(tokens, curr, *semicolonToken); ok {
+	if selectStatement, currCursor, ok := p.parseSelectStatement(curr, *semicolonToken); ok {
 		return &ast.TStatement{
 			Select: selectStatement,
 			Type:   ast.SelectType,
 		}, currCursor, ok
 	}
 
-	if insertStatemt, currCursor, ok := parseInsertStatement(tokens, curr, *semicolonToken); ok {
+	if insertStatemt, currCursor, ok := p.parseInsertStatement(curr, *semicolonToken); ok {
 		return &ast.TStatement{
 			Insert: insertStatemt,
 			Type:   ast.InsertType,
 		}, currCursor, ok
 	}
 
-	if createTableStatement, currCursor, ok := parseCreateTableStatement(tokens, curr, *semicolonToken); ok {
+	if createTableStatement, currCursor, ok := p.parseCreateTableStatement(curr, *semicolonToken); ok {
 		return &ast.TStatement{
 			CreateTable: createTableStatement,
 			Type:        ast.CreateTableType,
 		}, currCursor, ok
 	}
 
+	if updateStatement, currCursor, ok := p.parseUpdateStatement(curr, *semicolonToken); ok {
+		return &ast.TStatement{
+			Update: updateStatement,
+			Type:   ast.UpdateType,
+		}, currCursor, ok
+	}
+
+	if deleteStatement, currCursor, ok := p.parseDeleteStatement(curr, *semicolonToken); ok {
+		return &ast.TStatement{
+			Delete: deleteStatement,
+			Type:   ast.DeleteType,
+		}, currCursor, ok
+	}
+
+	if dropTableStatement, currCursor, ok := p.parseDropTableStatement(curr, *semicolonToken); ok {
+		return &ast.TStatement{
+			DropTable: dropTableStatement,
+			Type:      ast.DropTableType,
+		}, currCursor, ok
+	}
+
+	if alterTableStatement, currCursor, ok := p.parseAlterTableStatement(curr, *semicolonToken); ok {
+		return &ast.TStatement{
+			AlterTable: alterTableStatement,
+			Type:       ast.AlterTableType,
+		}, currCursor, ok
+	}
+
 	return nil, inputCursor, false
 }
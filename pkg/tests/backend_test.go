@@ -0,0 +1,2186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"pkg/backend"
+	"pkg/parser"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func execute(t testing.TB, mb *backend.TMemoryBackend, source string) (*backend.TResults, error) {
+	tree, err := parser.Parse(source)
+	if !assert.Nil(t, err, source) {
+		return nil, err
+	}
+
+	results, err := mb.ExecuteTree(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	return results[len(results)-1], nil
+}
+
+func TestBackend_Select(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	for _, source := range []string{
+		"CREATE TABLE users (id INT, name TEXT)",
+		"INSERT INTO users VALUES (1, 'alice')",
+		"INSERT INTO users VALUES (2, 'bob')",
+	} {
+		_, err := execute(t, mb, source)
+		assert.Nil(t, err, source)
+	}
+
+	tests := []struct {
+		source  string
+		columns []*backend.TResultColumn
+		rows    [][]backend.TValue
+	}{
+		{
+			source: "SELECT id, name FROM users",
+			columns: []*backend.TResultColumn{
+				{Name: "id", Type: backend.IntType},
+				{Name: "name", Type: backend.TextType},
+			},
+			rows: [][]backend.TValue{
+				{int64(1), "alice"},
+				{int64(2), "bob"},
+			},
+		},
+		{
+			source: "SELECT name, 'x', 7 FROM users",
+			columns: []*backend.TResultColumn{
+				{Name: "name", Type: backend.TextType},
+				{Name: "?column?", Type: backend.TextType},
+				{Name: "?column?", Type: backend.IntType},
+			},
+			rows: [][]backend.TValue{
+				{"alice", "x", int64(7)},
+				{"bob", "x", int64(7)},
+			},
+		},
+		{
+			source: "SELECT 1, 'one'",
+			columns: []*backend.TResultColumn{
+				{Name: "?column?", Type: backend.IntType},
+				{Name: "?column?", Type: backend.TextType},
+			},
+			rows: [][]backend.TValue{
+				{int64(1), "one"},
+			},
+		},
+		{
+			source: "SELECT id * 10 + 1, name || '!', id = 2 OR NOT name <> 'alice' FROM users",
+			columns: []*backend.TResultColumn{
+				{Name: "?column?", Type: backend.IntType},
+				{Name: "?column?", Type: backend.TextType},
+				{Name: "?column?", Type: backend.BoolType},
+			},
+			rows: [][]backend.TValue{
+				{int64(11), "alice!", true},
+				{int64(21), "bob!", true},
+			},
+		},
+		{
+			source: "SELECT -(1 + 2) * 3, 7 / 2, 7 % 2, 2 * 3 > 5 AND 1 >= 2",
+			columns: []*backend.TResultColumn{
+				{Name: "?column?", Type: backend.IntType},
+				{Name: "?column?", Type: backend.IntType},
+				{Name: "?column?", Type: backend.IntType},
+				{Name: "?column?", Type: backend.BoolType},
+			},
+			rows: [][]backend.TValue{
+				{int64(-9), int64(3), int64(1), false},
+			},
+		},
+		{
+			source: "SELECT -9223372036854775808, - -5, -2147483648, -1.5",
+			columns: []*backend.TResultColumn{
+				{Name: "?column?", Type: backend.BigIntType},
+				{Name: "?column?", Type: backend.IntType},
+				{Name: "?column?", Type: backend.IntType},
+				{Name: "?column?", Type: backend.NumericType},
+			},
+			rows: [][]backend.TValue{
+				{int64(math.MinInt64), int64(5), int64(math.MinInt32), backend.TNumeric("-1.5")},
+			},
+		},
+		{
+			source: "SELECT name FROM users WHERE id > 1 AND name <> 'alice'",
+			columns: []*backend.TResultColumn{
+				{Name: "name", Type: backend.TextType},
+			},
+			rows: [][]backend.TValue{
+				{"bob"},
+			},
+		},
+		{
+			source: "SELECT id FROM users WHERE id = 3",
+			columns: []*backend.TResultColumn{
+				{Name: "id", Type: backend.IntType},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.columns, results.Columns, test.source)
+		assert.Equal(t, test.rows, results.Rows, test.source)
+	}
+}
+
+func TestBackend_Errors(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, "CREATE TABLE users (id INT, name TEXT); INSERT INTO users VALUES (1, 'alice')")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		err    error
+	}{
+		{
+			source: "CREATE TABLE users (id INT)",
+			err:    backend.ErrTableAlreadyExists,
+		},
+		{
+			source: "CREATE TABLE t (id VARCHAR(0))",
+			err:    backend.ErrInvalidDatatype,
+		},
+		{
+			source: "SELECT id FROM missing",
+			err:    backend.ErrTableDoesNotExist,
+		},
+		{
+			source: "SELECT age FROM users",
+			err:    backend.ErrColumnDoesNotExist,
+		},
+		{
+			source: "INSERT INTO users VALUES (1)",
+			err:    backend.ErrMissingValues,
+		},
+		{
+			source: "INSERT INTO users VALUES ('one', 'alice')",
+			err:    backend.ErrInvalidValue,
+		},
+		{
+			source: "SELECT id = name FROM users",
+			err:    backend.ErrTypeMismatch,
+		},
+		{
+			source: "SELECT 1 + 'a'",
+			err:    backend.ErrTypeMismatch,
+		},
+		{
+			source: "SELECT id FROM users WHERE id",
+			err:    backend.ErrTypeMismatch,
+		},
+		{
+			source: "UPDATE users SET id = 1, id = 2",
+			err:    backend.ErrDuplicateColumn,
+		},
+		{
+			source: "UPDATE users SET age = 1",
+			err:    backend.ErrColumnDoesNotExist,
+		},
+		{
+			source: "UPDATE users SET id = 'one'",
+			err:    backend.ErrInvalidValue,
+		},
+		{
+			source: "DELETE FROM users WHERE name",
+			err:    backend.ErrTypeMismatch,
+		},
+		{
+			source: "ALTER TABLE users ADD COLUMN name TEXT",
+			err:    backend.ErrDuplicateColumn,
+		},
+		{
+			source: "ALTER TABLE users DROP COLUMN age",
+			err:    backend.ErrColumnDoesNotExist,
+		},
+		{
+			source: "ALTER TABLE users ADD COLUMN age INT DEFAULT 'old'",
+			err:    backend.ErrInvalidValue,
+		},
+		{
+			source: "SELECT 1 / 0",
+			err:    backend.ErrDivisionByZero,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), test.source)
+	}
+}
+
+func TestBackend_UpdateDelete(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (id INT, name TEXT);
+		INSERT INTO users VALUES (1, 'alice');
+		INSERT INTO users VALUES (2, 'bob');
+		INSERT INTO users VALUES (3, 'carol');
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source   string
+		affected uint
+		rows     [][]backend.TValue
+	}{
+		{
+			source:   "UPDATE users SET name = name || '!', id = id * 10 WHERE id >= 2",
+			affected: 2,
+			rows: [][]backend.TValue{
+				{int64(1), "alice"},
+				{int64(20), "bob!"},
+				{int64(30), "carol!"},
+			},
+		},
+		{
+			source:   "UPDATE users SET name = 'nobody' WHERE id = 5",
+			affected: 0,
+			rows: [][]backend.TValue{
+				{int64(1), "alice"},
+				{int64(20), "bob!"},
+				{int64(30), "carol!"},
+			},
+		},
+		{
+			source:   "DELETE FROM users WHERE name = 'bob!'",
+			affected: 1,
+			rows: [][]backend.TValue{
+				{int64(1), "alice"},
+				{int64(30), "carol!"},
+			},
+		},
+		{
+			source:   "DELETE FROM users",
+			affected: 2,
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.affected, results.RowsAffected, test.source)
+
+		results, err = execute(t, mb, "SELECT id, name FROM users")
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.rows, results.Rows, test.source)
+	}
+}
+
+func TestBackend_AlterTable(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (id INT, name TEXT);
+		INSERT INTO users VALUES (1, 'alice');
+		INSERT INTO users VALUES (2, 'bob');
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		query  string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "ALTER TABLE users ADD COLUMN age INT",
+			query:  "SELECT id, age FROM users",
+			rows: [][]backend.TValue{
+				{int64(1), nil},
+				{int64(2), nil},
+			},
+		},
+		{
+			source: "ALTER TABLE users ADD country TEXT DEFAULT 'nl'",
+			query:  "SELECT id, country FROM users",
+			rows: [][]backend.TValue{
+				{int64(1), "nl"},
+				{int64(2), "nl"},
+			},
+		},
+		{
+			source: "ALTER TABLE users DROP COLUMN age",
+			query:  "SELECT id, name, country FROM users",
+			rows: [][]backend.TValue{
+				{int64(1), "alice", "nl"},
+				{int64(2), "bob", "nl"},
+			},
+		},
+		{
+			source: "ALTER TABLE users RENAME name TO login",
+			query:  "SELECT login FROM users WHERE id = 2",
+			rows: [][]backend.TValue{
+				{"bob"},
+			},
+		},
+		{
+			source: "ALTER TABLE users RENAME TO accounts",
+			query:  "SELECT id FROM accounts",
+			rows: [][]backend.TValue{
+				{int64(1)},
+				{int64(2)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		_, err := execute(t, mb, test.source)
+		assert.Nil(t, err, test.source)
+
+		results, err := execute(t, mb, test.query)
+		assert.Nil(t, err, test.query)
+		assert.Equal(t, test.rows, results.Rows, test.query)
+	}
+
+	_, err = execute(t, mb, "SELECT id FROM users")
+	assert.True(t, errors.Is(err, backend.ErrTableDoesNotExist))
+
+	_, err = execute(t, mb, "DROP TABLE accounts; DROP TABLE IF EXISTS accounts")
+	assert.Nil(t, err)
+
+	_, err = execute(t, mb, "DROP TABLE accounts")
+	assert.True(t, errors.Is(err, backend.ErrTableDoesNotExist))
+}
+
+func TestBackend_AlterTableQualifiedCheck(t *testing.T) {
+	tests := []struct {
+		alter  string
+		insert string
+	}{
+		{alter: "ALTER TABLE c RENAME TO d", insert: "INSERT INTO d VALUES (%s)"},
+		{alter: "ALTER TABLE c RENAME a TO b", insert: "INSERT INTO c VALUES (%s)"},
+	}
+
+	for _, test := range tests {
+		mb := backend.NewMemoryBackend()
+
+		_, err := execute(t, mb, "CREATE TABLE c (a INT CHECK (c.a > 0), CHECK (c.a < 10))")
+		assert.Nil(t, err)
+
+		_, err = execute(t, mb, test.alter)
+		assert.Nil(t, err, test.alter)
+
+		_, err = execute(t, mb, fmt.Sprintf(test.insert, "5"))
+		assert.Nil(t, err, test.alter)
+
+		for _, value := range []string{"0", "10"} {
+			_, err = execute(t, mb, fmt.Sprintf(test.insert, value))
+			assert.True(t, errors.Is(err, backend.ErrConstraintViolation), "%s: %v", test.alter, err)
+		}
+	}
+
+	_, err := execute(t, backend.NewMemoryBackend(), "CREATE TABLE c (a INT CHECK (other.a > 0))")
+	assert.True(t, errors.Is(err, backend.ErrColumnDoesNotExist), "%v", err)
+}
+
+func TestBackend_Constraints(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (
+			id INT PRIMARY KEY,
+			email TEXT UNIQUE,
+			age INT DEFAULT 18 CHECK (age >= 18),
+			CHECK (id > 0)
+		);
+		CREATE TABLE pairs (a INT, b INT, PRIMARY KEY (a, b));
+		CREATE TABLE digits (d INT CHECK (d > 0) CHECK (d < 10));
+		INSERT INTO users VALUES (1, 'a@x', 20);
+		INSERT INTO pairs VALUES (1, 1);
+		INSERT INTO pairs VALUES (1, 2);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		err    error
+	}{
+		{
+			source: "INSERT INTO users VALUES (1, 'b@x', 30)",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO users VALUES (2, 'a@x', 30)",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO users VALUES (2, 'b@x', 17)",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO users VALUES (0, 'b@x', 30)",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO pairs VALUES (1, 2)",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO digits VALUES (0)",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO digits VALUES (10)",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO digits VALUES (9)",
+		},
+		{
+			source: "INSERT INTO users VALUES (2, 'b@x', 30)",
+		},
+		{
+			source: "UPDATE users SET email = 'a@x' WHERE id = 2",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "UPDATE users SET age = age - 5",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "ALTER TABLE users ADD COLUMN nickname TEXT NOT NULL",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "ALTER TABLE users ADD COLUMN nickname TEXT NOT NULL DEFAULT 'anon'",
+		},
+		{
+			source: "ALTER TABLE users ADD COLUMN code TEXT UNIQUE DEFAULT 'x'",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "ALTER TABLE users RENAME COLUMN age TO years",
+		},
+		{
+			source: "UPDATE users SET years = 10",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "CREATE TABLE broken (a INT PRIMARY KEY, b INT PRIMARY KEY)",
+			err:    backend.ErrInvalidConstraint,
+		},
+		{
+			source: "CREATE TABLE broken (a INT, UNIQUE (c))",
+			err:    backend.ErrColumnDoesNotExist,
+		},
+		{
+			source: "CREATE TABLE broken (a INT CHECK (a + 1))",
+			err:    backend.ErrTypeMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := execute(t, mb, test.source)
+		if test.err == nil {
+			assert.Nil(t, err, test.source)
+		} else {
+			assert.True(t, errors.Is(err, test.err), test.source)
+		}
+	}
+
+	results, err := execute(t, mb, "SELECT id, email, years, nickname FROM users")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]backend.TValue{
+		{int64(1), "a@x", int64(20), "anon"},
+		{int64(2), "b@x", int64(30), "anon"},
+	}, results.Rows)
+}
+
+func TestBackend_UniqueIndex(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE items (id INT PRIMARY KEY, code TEXT UNIQUE, note TEXT);
+		INSERT INTO items VALUES (1, 'a', 'x'), (2, 'b', 'y'), (3, NULL, 'z');
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		err    error
+	}{
+		{source: "UPDATE items SET id = 3 - id WHERE id < 3"},
+		{source: "UPDATE items SET id = id + 1"},
+		{source: "UPDATE items SET id = 4 WHERE id = 2", err: backend.ErrConstraintViolation},
+		{source: "INSERT INTO items VALUES (5, 'c', NULL), (6, 'c', NULL)", err: backend.ErrConstraintViolation},
+		{source: "INSERT INTO items VALUES (5, 'c', NULL)"},
+		{source: "INSERT INTO items VALUES (7, NULL, NULL)"},
+		{source: "INSERT INTO items VALUES (8, 'a', NULL)", err: backend.ErrConstraintViolation},
+		{source: "DELETE FROM items WHERE code = 'a'"},
+		{source: "INSERT INTO items VALUES (8, 'a', NULL)"},
+		{source: "ALTER TABLE items DROP COLUMN note"},
+		{source: "INSERT INTO items VALUES (9, 'b')", err: backend.ErrConstraintViolation},
+		{source: "INSERT INTO items VALUES (3, 'd')"},
+	}
+
+	for _, test := range tests {
+		_, err := execute(t, mb, test.source)
+		if test.err == nil {
+			assert.Nil(t, err, test.source)
+		} else {
+			assert.True(t, errors.Is(err, test.err), "%s: %v", test.source, err)
+		}
+	}
+
+	results, err := execute(t, mb, "SELECT id, code FROM items ORDER BY id")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]backend.TValue{
+		{int64(2), "b"},
+		{int64(3), "d"},
+		{int64(4), nil},
+		{int64(5), "c"},
+		{int64(7), nil},
+		{int64(8), "a"},
+	}, results.Rows)
+
+	_, err = execute(t, mb, `
+		CREATE TABLE pairs (a TEXT, b TEXT, UNIQUE (a, b));
+		CREATE TABLE zeros (d DOUBLE UNIQUE);
+		INSERT INTO zeros VALUES (0.0);
+	`)
+	assert.Nil(t, err)
+
+	_, err = execute(t, mb, "INSERT INTO pairs VALUES ('a\x00string:b', 'c'), ('a', 'b\x00string:c')")
+	assert.Nil(t, err)
+
+	_, err = execute(t, mb, "INSERT INTO zeros VALUES (CAST('-0' AS DOUBLE))")
+	assert.True(t, errors.Is(err, backend.ErrConstraintViolation), "%v", err)
+}
+
+func TestBackend_NonReservedKeywords(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE ev (id INT, first TEXT, key INT, last DATE, PRIMARY KEY (key));
+		CREATE TABLE date (date DATE);
+		INSERT INTO ev (id, first, key, last) VALUES (1, 'a', 10, '2024-01-02'), (2, NULL, 20, NULL);
+		INSERT INTO date SELECT last FROM ev WHERE last IS NOT NULL;
+	`)
+	assert.Nil(t, err)
+
+	results, err := execute(t, mb, "SELECT first, key, ev.last FROM ev ORDER BY first NULLS FIRST")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]backend.TValue{
+		{nil, int64(20), nil},
+		{"a", int64(10), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}, results.Rows)
+
+	results, err = execute(t, mb, "SELECT CAST(date.date AS TEXT) FROM date")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]backend.TValue{{"2024-01-02"}}, results.Rows)
+
+	_, err = execute(t, mb, "INSERT INTO ev VALUES (3, 'c', 10, NULL)")
+	assert.True(t, errors.Is(err, backend.ErrConstraintViolation), "%v", err)
+}
+
+func TestBackend_Datatypes(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE items (
+			id SMALLINT,
+			total BIGINT,
+			name VARCHAR(5),
+			price NUMERIC(5, 2),
+			ratio REAL,
+			weight DOUBLE PRECISION,
+			active BOOLEAN,
+			added DATE,
+			seen TIMESTAMP,
+			payload BLOB
+		);
+		INSERT INTO items VALUES (
+			1, 5000000000, 'apple', 12.345, 0.5, 1.25, 'yes',
+			'2024-02-29', '2024-02-29 10:30:00', '\x0aff'
+		);
+	`)
+	assert.Nil(t, err)
+
+	results, err := execute(t, mb, "SELECT id, total + 1, price, active, added + 1, seen, payload FROM items")
+	assert.Nil(t, err)
+	assert.Equal(t, []backend.EColumnType{
+		backend.SmallIntType,
+		backend.BigIntType,
+		backend.NumericType,
+		backend.BoolType,
+		backend.DateType,
+		backend.TimestampType,
+		backend.BlobType,
+	}, []backend.EColumnType{
+		results.Columns[0].Type,
+		results.Columns[1].Type,
+		results.Columns[2].Type,
+		results.Columns[3].Type,
+		results.Columns[4].Type,
+		results.Columns[5].Type,
+		results.Columns[6].Type,
+	})
+
+	row := results.Rows[0]
+	assert.Equal(t, int64(1), row[0])
+	assert.Equal(t, int64(5000000001), row[1])
+	assert.Equal(t, backend.TNumeric("12.35"), row[2])
+	assert.Equal(t, true, row[3])
+	assert.Equal(t, "2024-03-01", backend.FormatValue(row[4], backend.DateType))
+	assert.Equal(t, "2024-02-29 10:30:00", backend.FormatValue(row[5], backend.TimestampType))
+	assert.Equal(t, []byte{0x0a, 0xff}, row[6])
+
+	_, err = execute(t, mb, `
+		CREATE TABLE floats (r REAL, d DOUBLE);
+		INSERT INTO floats VALUES (0.1, 1e300), (1234567, 0.5), (0.00001, -123456789.25);
+	`)
+	assert.Nil(t, err)
+
+	results, err = execute(t, mb, "SELECT r, d FROM floats")
+	assert.Nil(t, err)
+
+	formatted := [][]string{}
+	for _, row := range results.Rows {
+		formatted = append(formatted, []string{
+			backend.FormatValue(row[0], results.Columns[0].Type),
+			backend.FormatValue(row[1], results.Columns[1].Type),
+		})
+	}
+	assert.Equal(t, [][]string{
+		{"0.1", "1e+300"},
+		{"1.234567e+06", "0.5"},
+		{"1e-05", "-123456789.25"},
+	}, formatted)
+
+	results, err = execute(t, mb, "SELECT id FROM items WHERE added < '2024-03-01' AND weight > 1")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results.Rows))
+
+	tests := []struct {
+		source string
+		err    error
+	}{
+		{
+			source: "UPDATE items SET id = 40000",
+			err:    backend.ErrOutOfRange,
+		},
+		{
+			source: "UPDATE items SET name = 'banana'",
+			err:    backend.ErrOutOfRange,
+		},
+		{
+			source: "UPDATE items SET price = 1000",
+			err:    backend.ErrOutOfRange,
+		},
+		{
+			source: "UPDATE items SET added = 'yesterday'",
+			err:    backend.ErrInvalidValue,
+		},
+		{
+			source: "UPDATE items SET active = 'maybe'",
+			err:    backend.ErrInvalidValue,
+		},
+		{
+			source: "SELECT total * 5000000000 FROM items",
+			err:    backend.ErrOutOfRange,
+		},
+		{
+			source: "SELECT 2147483647::INT + 1",
+			err:    backend.ErrOutOfRange,
+		},
+		{
+			source: "SELECT id + 32767::SMALLINT FROM items",
+			err:    backend.ErrOutOfRange,
+		},
+		{
+			source: "SELECT 2147483647::BIGINT + 1",
+		},
+		{
+			source: "SELECT added = 1 FROM items",
+			err:    backend.ErrTypeMismatch,
+		},
+		{
+			source: "CREATE TABLE broken (price NUMERIC(2, 3))",
+			err:    backend.ErrInvalidDatatype,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), test.source)
+	}
+}
+
+func TestBackend_Numeric(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE amounts (id INT, v NUMERIC(30, 2), d DOUBLE PRECISION);
+		INSERT INTO amounts VALUES
+			(1, 1234567890123456789012345678.91, 0.5),
+			(2, 12345678901234567.89, 0.1),
+			(3, 1.005, 2);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT v FROM amounts ORDER BY id",
+			rows: [][]backend.TValue{
+				{backend.TNumeric("1234567890123456789012345678.91")},
+				{backend.TNumeric("12345678901234567.89")},
+				{backend.TNumeric("1.01")},
+			},
+		},
+		{
+			source: "SELECT id FROM amounts WHERE v = 12345678901234567.88",
+			rows:   nil,
+		},
+		{
+			source: "SELECT id FROM amounts WHERE v = 12345678901234567.89",
+			rows:   [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: "SELECT 0.1 + 0.1 + 0.1, 0.1 + 0.1 + 0.1 = 0.3",
+			rows:   [][]backend.TValue{{backend.TNumeric("0.3"), true}},
+		},
+		{
+			source: "SELECT CAST(0.1 AS NUMERIC(10, 2)) + CAST(0.2 AS NUMERIC(10, 2)) = 0.3",
+			rows:   [][]backend.TValue{{true}},
+		},
+		{
+			source: "SELECT 1 / 3.0, 7.5 % 2, 2.50 * 4, -(0.5)",
+			rows: [][]backend.TValue{{
+				backend.TNumeric("0.33333333333333333333"),
+				backend.TNumeric("1.5"),
+				backend.TNumeric("10"),
+				backend.TNumeric("-0.5"),
+			}},
+		},
+		{
+			source: "SELECT CAST(2.5 AS INT), CAST(-2.5 AS INT), '1e3'::numeric, 0.5 + d FROM amounts WHERE id = 1",
+			rows:   [][]backend.TValue{{int64(3), int64(-3), backend.TNumeric("1000"), 1.0}},
+		},
+		{
+			source: "SELECT sum(v), avg(id) FROM amounts WHERE id > 1",
+			rows:   [][]backend.TValue{{backend.TNumeric("12345678901234568.9"), backend.TNumeric("2.5")}},
+		},
+		{
+			source: "SELECT a.id, b.id FROM amounts a JOIN amounts b ON a.d = b.v ORDER BY 1",
+			rows:   nil,
+		},
+		{
+			source: "SELECT a.id, b.id FROM amounts a JOIN amounts b ON a.id + 0.01 = b.v ORDER BY 1",
+			rows:   [][]backend.TValue{{int64(1), int64(3)}},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "INSERT INTO amounts (v) VALUES (10000000000000000000000000000)", err: backend.ErrOutOfRange},
+		{source: "INSERT INTO amounts (v) VALUES ('1/2')", err: backend.ErrInvalidValue},
+		{source: "INSERT INTO amounts (v) VALUES ('1e100000')", err: backend.ErrOutOfRange},
+		{source: "SELECT 1.5 / 0", err: backend.ErrDivisionByZero},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), "%s: %v", test.source, err)
+	}
+}
+
+func TestBackend_Null(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (id INT, name TEXT, active BOOLEAN);
+		INSERT INTO users VALUES (1, 'alice', TRUE);
+		INSERT INTO users VALUES (2, NULL, FALSE);
+		INSERT INTO users VALUES (3, 'carol', NULL);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT NULL AND FALSE, NULL AND TRUE, NULL OR TRUE, NULL OR FALSE, NOT NULL",
+			rows:   [][]backend.TValue{{false, nil, true, nil, nil}},
+		},
+		{
+			source: "SELECT NULL = NULL, NULL IS NULL, 1 + NULL, 'a' || NULL",
+			rows:   [][]backend.TValue{{nil, true, nil, nil}},
+		},
+		{
+			source: "SELECT id FROM users WHERE name IS NULL",
+			rows:   [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: "SELECT id FROM users WHERE name IS NOT NULL AND active IS NOT FALSE",
+			rows:   [][]backend.TValue{{int64(1)}, {int64(3)}},
+		},
+		{
+			source: "SELECT id FROM users WHERE active OR name = 'carol'",
+			rows:   [][]backend.TValue{{int64(1)}, {int64(3)}},
+		},
+		{
+			source: "SELECT id FROM users WHERE NOT active",
+			rows:   [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: "SELECT id FROM users WHERE name IS DISTINCT FROM 'alice'",
+			rows:   [][]backend.TValue{{int64(2)}, {int64(3)}},
+		},
+		{
+			source: "SELECT id FROM users WHERE active IS NOT DISTINCT FROM NULL",
+			rows:   [][]backend.TValue{{int64(3)}},
+		},
+		{
+			source: "SELECT id = 1 IS TRUE FROM users WHERE id < 3",
+			rows:   [][]backend.TValue{{true}, {false}},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	_, err = execute(t, mb, "SELECT id FROM users WHERE id IS TRUE")
+	assert.True(t, errors.Is(err, backend.ErrTypeMismatch))
+}
+
+func TestBackend_InsertColumns(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (
+			id INT PRIMARY KEY,
+			name TEXT NOT NULL DEFAULT 'anon',
+			age INT DEFAULT 18
+		)
+	`)
+	assert.Nil(t, err)
+
+	results, err := execute(t, mb, "INSERT INTO users (id, age) VALUES (1, 30), (2, 40), (3, NULL)")
+	assert.Nil(t, err)
+	assert.Equal(t, uint(3), results.RowsAffected)
+
+	_, err = execute(t, mb, "INSERT INTO users (name, id) VALUES ('dave', 4)")
+	assert.Nil(t, err)
+
+	_, err = execute(t, mb, "INSERT INTO users VALUES (5, 'eve', 25), (6, 'frank', 26)")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		err    error
+	}{
+		{
+			source: "INSERT INTO users (id, id) VALUES (7, 7)",
+			err:    backend.ErrDuplicateColumn,
+		},
+		{
+			source: "INSERT INTO users (id, nickname) VALUES (7, 'x')",
+			err:    backend.ErrColumnDoesNotExist,
+		},
+		{
+			source: "INSERT INTO users (id, name) VALUES (7)",
+			err:    backend.ErrMissingValues,
+		},
+		{
+			source: "INSERT INTO users (id) VALUES (7), (8, 'x')",
+			err:    backend.ErrMissingValues,
+		},
+		{
+			source: "INSERT INTO users (id) VALUES (7), (7)",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO users (id, name) VALUES (8, 'x'), (9, NULL)",
+			err:    backend.ErrConstraintViolation,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), test.source)
+	}
+
+	results, err = execute(t, mb, "SELECT id, name, age FROM users")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]backend.TValue{
+		{int64(1), "anon", int64(30)},
+		{int64(2), "anon", int64(40)},
+		{int64(3), "anon", nil},
+		{int64(4), "dave", int64(18)},
+		{int64(5), "eve", int64(25)},
+		{int64(6), "frank", int64(26)},
+	}, results.Rows)
+}
+
+func TestBackend_InsertSelect(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (id INT, name TEXT, age INT);
+		INSERT INTO users VALUES (1, 'alice', 30), (2, 'bob', 17), (3, 'carol', 45);
+		CREATE TABLE adults AS SELECT id, name, age >= 40 FROM users WHERE age >= 18;
+		CREATE TABLE names (id BIGINT PRIMARY KEY, name VARCHAR(10), tag TEXT DEFAULT 'copy');
+	`)
+	assert.Nil(t, err)
+
+	table, err := mb.Table("adults")
+	assert.Nil(t, err)
+	assert.Equal(t, []*backend.TColumn{
+		{Name: "id", Type: backend.IntType},
+		{Name: "name", Type: backend.TextType},
+		{Name: "?column?", Type: backend.BoolType},
+	}, table.Columns)
+
+	results, err := execute(t, mb, "INSERT INTO names (id, name) SELECT id * 10, name FROM adults")
+	assert.Nil(t, err)
+	assert.Equal(t, uint(2), results.RowsAffected)
+
+	results, err = execute(t, mb, "SELECT id, name, tag FROM names")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]backend.TValue{
+		{int64(10), "alice", "copy"},
+		{int64(30), "carol", "copy"},
+	}, results.Rows)
+
+	tests := []struct {
+		source string
+		err    error
+	}{
+		{
+			source: "INSERT INTO names SELECT id, name FROM users",
+			err:    backend.ErrMissingValues,
+		},
+		{
+			source: "INSERT INTO names (id) SELECT id * 10 FROM users",
+			err:    backend.ErrConstraintViolation,
+		},
+		{
+			source: "INSERT INTO names (id, name) SELECT name, id FROM users",
+			err:    backend.ErrInvalidValue,
+		},
+		{
+			source: "CREATE TABLE adults AS SELECT 1",
+			err:    backend.ErrTableAlreadyExists,
+		},
+		{
+			source: "CREATE TABLE pairs AS SELECT id, id FROM users",
+			err:    backend.ErrDuplicateColumn,
+		},
+		{
+			source: "CREATE TABLE missing AS SELECT id FROM nowhere",
+			err:    backend.ErrTableDoesNotExist,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), test.source)
+	}
+
+	results, err = execute(t, mb, "SELECT id FROM names")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results.Rows))
+}
+
+func TestBackend_Aliases(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (id INT, name TEXT);
+		INSERT INTO users VALUES (1, 'alice'), (2, 'bob');
+	`)
+	assert.Nil(t, err)
+
+	results, err := execute(t, mb, "SELECT u.id AS key, name n, u.id * 2 doubled FROM users AS u WHERE u.name = 'bob'")
+	assert.Nil(t, err)
+	assert.Equal(t, []*backend.TResultColumn{
+		{Name: "key", Type: backend.IntType},
+		{Name: "n", Type: backend.TextType},
+		{Name: "doubled", Type: backend.IntType},
+	}, results.Columns)
+	assert.Equal(t, [][]backend.TValue{{int64(2), "bob", int64(4)}}, results.Rows)
+
+	results, err = execute(t, mb, "SELECT users.name FROM users WHERE users.id = 1")
+	assert.Nil(t, err)
+	assert.Equal(t, "name", results.Columns[0].Name)
+	assert.Equal(t, [][]backend.TValue{{"alice"}}, results.Rows)
+
+	_, err = execute(t, mb, "SELECT users.id FROM users u")
+	assert.True(t, errors.Is(err, backend.ErrColumnDoesNotExist))
+
+	_, err = execute(t, mb, "SELECT x.id FROM users")
+	assert.True(t, errors.Is(err, backend.ErrColumnDoesNotExist))
+}
+
+func TestBackend_OrderBy(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE scores (name TEXT, score INT);
+		INSERT INTO scores VALUES ('a', 3), ('b', NULL), ('c', 1), ('d', 3), ('e', 2);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT name FROM scores ORDER BY score, name DESC",
+			rows:   [][]backend.TValue{{"c"}, {"e"}, {"d"}, {"a"}, {"b"}},
+		},
+		{
+			source: "SELECT name FROM scores ORDER BY score DESC",
+			rows:   [][]backend.TValue{{"b"}, {"a"}, {"d"}, {"e"}, {"c"}},
+		},
+		{
+			source: "SELECT name FROM scores ORDER BY score DESC NULLS LAST LIMIT 2",
+			rows:   [][]backend.TValue{{"a"}, {"d"}},
+		},
+		{
+			source: "SELECT name, score * 10 AS points FROM scores ORDER BY points NULLS FIRST LIMIT 2 OFFSET 1",
+			rows:   [][]backend.TValue{{"c", int64(10)}, {"e", int64(20)}},
+		},
+		{
+			source: "SELECT name FROM scores ORDER BY 1 DESC LIMIT 10 OFFSET 3",
+			rows:   [][]backend.TValue{{"b"}, {"a"}},
+		},
+		{
+			source: "SELECT name FROM scores LIMIT 2",
+			rows:   [][]backend.TValue{{"a"}, {"b"}},
+		},
+		{
+			source: "SELECT name FROM scores ORDER BY score LIMIT 0",
+			rows:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.rows, results.Rows, test.source)
+	}
+
+	_, err = execute(t, mb, "SELECT name FROM scores ORDER BY 2")
+	assert.True(t, errors.Is(err, backend.ErrInvalidValue))
+
+	_, err = execute(t, mb, "SELECT name FROM scores LIMIT -1")
+	assert.True(t, errors.Is(err, backend.ErrInvalidValue))
+
+	_, err = execute(t, mb, "SELECT name FROM scores LIMIT 'a'")
+	assert.True(t, errors.Is(err, backend.ErrTypeMismatch))
+}
+
+func TestBackend_Aggregates(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE employees (name TEXT, dept TEXT, salary INT);
+		INSERT INTO employees VALUES
+			('ann', 'eng', 100),
+			('bob', 'eng', 80),
+			('cat', 'ops', 80),
+			('dan', 'ops', NULL),
+			('eve', 'eng', 100);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source  string
+		columns []*backend.TResultColumn
+		rows    [][]backend.TValue
+	}{
+		{
+			source: "SELECT COUNT(*), COUNT(salary), COUNT(DISTINCT salary), SUM(salary), MIN(name), MAX(salary) FROM employees",
+			columns: []*backend.TResultColumn{
+				{Name: "count", Type: backend.BigIntType},
+				{Name: "count", Type: backend.BigIntType},
+				{Name: "count", Type: backend.BigIntType},
+				{Name: "sum", Type: backend.BigIntType},
+				{Name: "min", Type: backend.TextType},
+				{Name: "max", Type: backend.IntType},
+			},
+			rows: [][]backend.TValue{{int64(5), int64(4), int64(2), int64(360), "ann", int64(100)}},
+		},
+		{
+			source: "SELECT dept, AVG(salary) AS average FROM employees GROUP BY dept ORDER BY dept",
+			columns: []*backend.TResultColumn{
+				{Name: "dept", Type: backend.TextType},
+				{Name: "average", Type: backend.NumericType},
+			},
+			rows: [][]backend.TValue{{"eng", backend.TNumeric("93.33333333333333333333")}, {"ops", backend.TNumeric("80")}},
+		},
+		{
+			source: "SELECT dept, COUNT(*) FROM employees GROUP BY 1 HAVING SUM(salary) > 100",
+			columns: []*backend.TResultColumn{
+				{Name: "dept", Type: backend.TextType},
+				{Name: "count", Type: backend.BigIntType},
+			},
+			rows: [][]backend.TValue{{"eng", int64(3)}},
+		},
+		{
+			source: "SELECT salary / 10 AS tens, COUNT(*) FROM employees GROUP BY tens ORDER BY COUNT(*) DESC, tens",
+			columns: []*backend.TResultColumn{
+				{Name: "tens", Type: backend.IntType},
+				{Name: "count", Type: backend.BigIntType},
+			},
+			rows: [][]backend.TValue{{int64(8), int64(2)}, {int64(10), int64(2)}, {nil, int64(1)}},
+		},
+		{
+			source: "SELECT COUNT(*), SUM(salary), AVG(salary) FROM employees WHERE dept = 'hr'",
+			columns: []*backend.TResultColumn{
+				{Name: "count", Type: backend.BigIntType},
+				{Name: "sum", Type: backend.BigIntType},
+				{Name: "avg", Type: backend.NumericType},
+			},
+			rows: [][]backend.TValue{{int64(0), nil, nil}},
+		},
+		{
+			source: "SELECT dept FROM employees WHERE dept = 'hr' GROUP BY dept",
+			columns: []*backend.TResultColumn{
+				{Name: "dept", Type: backend.TextType},
+			},
+			rows: nil,
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.columns, results.Columns, test.source)
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "SELECT name, COUNT(*) FROM employees", err: backend.ErrInvalidGrouping},
+		{source: "SELECT name FROM employees GROUP BY dept", err: backend.ErrInvalidGrouping},
+		{source: "SELECT dept FROM employees GROUP BY dept HAVING salary > 1", err: backend.ErrInvalidGrouping},
+		{source: "SELECT name FROM employees WHERE COUNT(*) > 1", err: backend.ErrInvalidGrouping},
+		{source: "SELECT MAX(COUNT(*)) FROM employees", err: backend.ErrInvalidGrouping},
+		{source: "SELECT SUM(name) FROM employees", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT MAX(*) FROM employees", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT MEDIAN(salary) FROM employees", err: backend.ErrFunctionDoesNotExist},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), test.source)
+	}
+}
+
+func TestBackend_Joins(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (id INT, name TEXT);
+		CREATE TABLE orders (id INT, user_id INT, total INT);
+		INSERT INTO users VALUES (1, 'ann'), (2, 'bob'), (3, 'cat');
+		INSERT INTO orders VALUES (10, 1, 5), (11, 1, 7), (12, 2, 3), (13, 4, 9);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT name, total FROM users JOIN orders ON users.id = orders.user_id ORDER BY total",
+			rows:   [][]backend.TValue{{"bob", int64(3)}, {"ann", int64(5)}, {"ann", int64(7)}},
+		},
+		{
+			source: "SELECT u.name, o.id FROM users u LEFT JOIN orders o ON u.id = o.user_id ORDER BY 1, 2",
+			rows: [][]backend.TValue{
+				{"ann", int64(10)},
+				{"ann", int64(11)},
+				{"bob", int64(12)},
+				{"cat", nil},
+			},
+		},
+		{
+			source: "SELECT u.name, o.id FROM users u RIGHT OUTER JOIN orders o ON u.id = o.user_id ORDER BY o.id",
+			rows: [][]backend.TValue{
+				{"ann", int64(10)},
+				{"ann", int64(11)},
+				{"bob", int64(12)},
+				{nil, int64(13)},
+			},
+		},
+		{
+			source: "SELECT u.name, o.id FROM users u FULL JOIN orders o ON u.id = o.user_id AND o.total > 4 ORDER BY 1, 2",
+			rows: [][]backend.TValue{
+				{"ann", int64(10)},
+				{"ann", int64(11)},
+				{"bob", nil},
+				{"cat", nil},
+				{nil, int64(12)},
+				{nil, int64(13)},
+			},
+		},
+		{
+			source: "SELECT COUNT(*) FROM users CROSS JOIN orders",
+			rows:   [][]backend.TValue{{int64(12)}},
+		},
+		{
+			source: "SELECT COUNT(*) FROM users, orders WHERE users.id = orders.user_id",
+			rows:   [][]backend.TValue{{int64(3)}},
+		},
+		{
+			source: "SELECT id, users.name, orders.total FROM users JOIN orders USING (id)",
+			rows:   nil,
+		},
+		{
+			source: "SELECT a.name, b.name FROM users a JOIN users b ON a.id + 1 = b.id ORDER BY 1",
+			rows:   [][]backend.TValue{{"ann", "bob"}, {"bob", "cat"}},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	_, err = execute(t, mb, `
+		CREATE TABLE profiles (user_id INT, bio TEXT);
+		INSERT INTO profiles VALUES (1, 'hi'), (5, 'ghost');
+	`)
+	assert.Nil(t, err)
+
+	results, err := execute(t, mb, `
+		SELECT user_id, orders.id, bio
+		FROM orders FULL JOIN profiles USING (user_id)
+		ORDER BY user_id, orders.id
+	`)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]backend.TValue{
+		{int64(1), int64(10), "hi"},
+		{int64(1), int64(11), "hi"},
+		{int64(2), int64(12), nil},
+		{int64(4), int64(13), nil},
+		{int64(5), nil, "ghost"},
+	}, results.Rows)
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "SELECT id FROM users JOIN orders ON users.id = orders.user_id", err: backend.ErrAmbiguousColumn},
+		{source: "SELECT name FROM users JOIN orders ON name = 1", err: backend.ErrTypeMismatch},
+		{source: "SELECT name FROM users JOIN orders ON COUNT(*) > 1", err: backend.ErrInvalidGrouping},
+		{source: "SELECT name FROM users JOIN orders USING (name)", err: backend.ErrColumnDoesNotExist},
+		{source: "SELECT name FROM users JOIN missing ON true", err: backend.ErrTableDoesNotExist},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), test.source)
+	}
+}
+
+func TestBackend_JoinStrategies(t *testing.T) {
+	for _, order := range []string{"", " ORDER BY k"} {
+		mb := backend.NewMemoryBackend()
+
+		_, err := execute(t, mb, `
+			CREATE TABLE src (k INT, v TEXT);
+			INSERT INTO src VALUES (3, 'c'), (1, 'a'), (NULL, 'n'), (2, 'b'), (3, 'cc'), (5, 'e');
+			CREATE TABLE dst (k REAL, w TEXT);
+			INSERT INTO dst VALUES (3.0, 'x'), (NULL, 'y'), (4.0, 'z'), (1.0, 'u'), (3.0, 'xx');
+		`)
+		assert.Nil(t, err)
+
+		// Sorted copies make the equi-joins below run as merge joins, the
+		// unsorted originals as hash joins.
+		_, err = execute(t, mb, `
+			CREATE TABLE l AS SELECT k, v FROM src`+order+`;
+			CREATE TABLE r AS SELECT k, w FROM dst`+order+`;
+		`)
+		assert.Nil(t, err)
+
+		for _, join := range []string{"JOIN", "LEFT JOIN", "RIGHT JOIN", "FULL JOIN"} {
+			expected, err := execute(t, mb, "SELECT l.v, r.w FROM l "+join+" r ON l.k <= r.k AND l.k >= r.k ORDER BY 1, 2")
+			assert.Nil(t, err, join)
+
+			for _, condition := range []string{"ON l.k = r.k", "ON r.k = l.k AND l.v <> 'zz'", "USING (k)"} {
+				source := "SELECT l.v, r.w FROM l " + join + " r " + condition + " ORDER BY 1, 2"
+
+				results, err := execute(t, mb, source)
+				if assert.Nil(t, err, source) {
+					assert.Equal(t, expected.Rows, results.Rows, source+order)
+				}
+			}
+		}
+	}
+}
+
+func TestBackend_Star(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (id INT, name TEXT);
+		CREATE TABLE orders (id INT, total INT);
+		INSERT INTO users VALUES (1, 'ann'), (2, 'bob');
+		INSERT INTO orders VALUES (1, 5), (3, 7);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source  string
+		columns []string
+		rows    [][]backend.TValue
+	}{
+		{
+			source:  "SELECT * FROM users",
+			columns: []string{"id", "name"},
+			rows:    [][]backend.TValue{{int64(1), "ann"}, {int64(2), "bob"}},
+		},
+		{
+			source:  "SELECT *, id * 10 AS tens FROM users ORDER BY tens DESC",
+			columns: []string{"id", "name", "tens"},
+			rows:    [][]backend.TValue{{int64(2), "bob", int64(20)}, {int64(1), "ann", int64(10)}},
+		},
+		{
+			source:  "SELECT o.*, u.name FROM users u JOIN orders o ON u.id = o.id",
+			columns: []string{"id", "total", "name"},
+			rows:    [][]backend.TValue{{int64(1), int64(5), "ann"}},
+		},
+		{
+			source:  "SELECT * FROM users, orders WHERE users.id = orders.id",
+			columns: []string{"id", "name", "id", "total"},
+			rows:    [][]backend.TValue{{int64(1), "ann", int64(1), int64(5)}},
+		},
+		{
+			source:  "SELECT * FROM users FULL JOIN orders USING (id) ORDER BY id",
+			columns: []string{"id", "name", "total"},
+			rows: [][]backend.TValue{
+				{int64(1), "ann", int64(5)},
+				{int64(2), "bob", nil},
+				{int64(3), nil, int64(7)},
+			},
+		},
+		{
+			source:  "SELECT orders.* FROM users FULL JOIN orders USING (id) ORDER BY id",
+			columns: []string{"id", "total"},
+			rows: [][]backend.TValue{
+				{int64(1), int64(5)},
+				{int64(3), int64(7)},
+				{nil, nil},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		columns := []string{}
+		for _, column := range results.Columns {
+			columns = append(columns, column.Name)
+		}
+
+		assert.Equal(t, test.columns, columns, test.source)
+		assert.Equal(t, test.rows, results.Rows, test.source)
+	}
+
+	_, err = execute(t, mb, "CREATE TABLE copy AS SELECT * FROM users")
+	assert.Nil(t, err)
+
+	table, err := mb.Table("copy")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "id", table.Columns[0].Name)
+		assert.Equal(t, "name", table.Columns[1].Name)
+		assert.Len(t, table.Rows, 2)
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "SELECT *", err: backend.ErrInvalidStatement},
+		{source: "SELECT x.* FROM users", err: backend.ErrTableDoesNotExist},
+		{source: "SELECT * FROM users GROUP BY id", err: backend.ErrInvalidGrouping},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), test.source)
+	}
+}
+
+func TestBackend_Subqueries(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE users (id INT, name TEXT);
+		CREATE TABLE orders (id INT, user_id INT, total INT);
+		INSERT INTO users VALUES (1, 'ann'), (2, 'bob'), (3, 'cid');
+		INSERT INTO orders VALUES (1, 1, 5), (2, 1, 7), (3, 2, 4), (4, NULL, 9);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source  string
+		columns []string
+		rows    [][]backend.TValue
+	}{
+		{
+			source:  "SELECT (SELECT max(total) FROM orders), (SELECT 1 WHERE false)",
+			columns: []string{"max", "?column?"},
+			rows:    [][]backend.TValue{{int64(9), nil}},
+		},
+		{
+			source:  "SELECT name FROM users WHERE id IN (SELECT user_id FROM orders) ORDER BY name",
+			columns: []string{"name"},
+			rows:    [][]backend.TValue{{"ann"}, {"bob"}},
+		},
+		{
+			source:  "SELECT name FROM users WHERE id NOT IN (SELECT user_id FROM orders)",
+			columns: []string{"name"},
+			rows:    nil,
+		},
+		{
+			source:  "SELECT id, id NOT IN (SELECT user_id FROM orders WHERE user_id IS NOT NULL) FROM users ORDER BY id",
+			columns: []string{"id", "?column?"},
+			rows:    [][]backend.TValue{{int64(1), false}, {int64(2), false}, {int64(3), true}},
+		},
+		{
+			source:  "SELECT name FROM users u WHERE EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND total > 4)",
+			columns: []string{"name"},
+			rows:    [][]backend.TValue{{"ann"}},
+		},
+		{
+			source: `SELECT name, (SELECT sum(total) FROM orders WHERE user_id = users.id) AS spent
+				FROM users ORDER BY spent DESC NULLS LAST`,
+			columns: []string{"name", "spent"},
+			rows:    [][]backend.TValue{{"ann", int64(12)}, {"bob", int64(4)}, {"cid", nil}},
+		},
+		{
+			source:  "SELECT id FROM orders o WHERE total > (SELECT avg(total) FROM orders WHERE user_id = o.user_id)",
+			columns: []string{"id"},
+			rows:    [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: `SELECT t.user_id, t.spent FROM (
+					SELECT user_id, sum(total) AS spent FROM orders GROUP BY user_id
+				) AS t JOIN users ON users.id = t.user_id WHERE spent > 4`,
+			columns: []string{"user_id", "spent"},
+			rows:    [][]backend.TValue{{int64(1), int64(12)}},
+		},
+		{
+			source:  "SELECT count(*) FROM (SELECT * FROM users WHERE id > 1) x",
+			columns: []string{"count"},
+			rows:    [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: `SELECT name FROM users u WHERE EXISTS (
+					SELECT 1 FROM orders WHERE total = (SELECT max(total) FROM orders o WHERE o.user_id = u.id)
+				)`,
+			columns: []string{"name"},
+			rows:    [][]backend.TValue{{"ann"}, {"bob"}},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		columns := []string{}
+		for _, column := range results.Columns {
+			columns = append(columns, column.Name)
+		}
+
+		assert.Equal(t, test.columns, columns, test.source)
+		assert.Equal(t, test.rows, results.Rows, test.source)
+	}
+
+	_, err = execute(t, mb, "UPDATE orders SET total = 0 WHERE user_id IN (SELECT id FROM users WHERE name = 'bob')")
+	assert.Nil(t, err)
+
+	_, err = execute(t, mb, "DELETE FROM orders WHERE total = (SELECT min(total) FROM orders)")
+	assert.Nil(t, err)
+
+	results, err := execute(t, mb, "SELECT id FROM orders ORDER BY id")
+	if assert.Nil(t, err) {
+		assert.Equal(t, [][]backend.TValue{{int64(1)}, {int64(2)}, {int64(4)}}, results.Rows)
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "SELECT (SELECT id FROM users)", err: backend.ErrInvalidSubquery},
+		{source: "SELECT (SELECT id, name FROM users)", err: backend.ErrInvalidSubquery},
+		{source: "SELECT 1 WHERE 1 IN (SELECT name FROM users)", err: backend.ErrTypeMismatch},
+		{source: "SELECT (SELECT missing FROM users)", err: backend.ErrColumnDoesNotExist},
+		{source: "SELECT x.id FROM (SELECT id FROM users) AS y", err: backend.ErrColumnDoesNotExist},
+		{source: "CREATE TABLE bad (id INT CHECK (id IN (SELECT id FROM users)))", err: backend.ErrInvalidSubquery},
+		{source: "CREATE TABLE bad (id INT DEFAULT (SELECT max(id) FROM users))", err: backend.ErrInvalidSubquery},
+		{source: "ALTER TABLE users ADD COLUMN bad BOOLEAN DEFAULT EXISTS (SELECT 1)", err: backend.ErrInvalidSubquery},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), "%s: %v", test.source, err)
+	}
+}
+
+func TestBackend_Predicates(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE items (id INT, name TEXT, price INT DEFAULT 0 NOT NULL);
+		INSERT INTO items VALUES (1, 'Apple', 3), (2, 'apricot', 8), (3, '100%', 5), (4, NULL, 12);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT id FROM items WHERE id IN (1, 3, 5) ORDER BY id",
+			rows:   [][]backend.TValue{{int64(1)}, {int64(3)}},
+		},
+		{
+			source: "SELECT 1 IN (2, NULL), 1 NOT IN (2, 3), NULL IN (1), 1 IN (1, NULL)",
+			rows:   [][]backend.TValue{{nil, true, nil, true}},
+		},
+		{
+			source: "SELECT id FROM items WHERE price BETWEEN 4 AND 10 AND id > 0 ORDER BY id",
+			rows:   [][]backend.TValue{{int64(2)}, {int64(3)}},
+		},
+		{
+			source: "SELECT id FROM items WHERE price NOT BETWEEN 4 AND 10 ORDER BY id",
+			rows:   [][]backend.TValue{{int64(1)}, {int64(4)}},
+		},
+		{
+			source: "SELECT 5 BETWEEN 1 AND NULL, 5 BETWEEN 6 AND NULL",
+			rows:   [][]backend.TValue{{nil, false}},
+		},
+		{
+			source: "SELECT id FROM items WHERE name LIKE 'ap%' ORDER BY id",
+			rows:   [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: "SELECT id FROM items WHERE name ILIKE 'ap%' ORDER BY id",
+			rows:   [][]backend.TValue{{int64(1)}, {int64(2)}},
+		},
+		{
+			source: "SELECT id FROM items WHERE name NOT LIKE '_p%' ORDER BY id",
+			rows:   [][]backend.TValue{{int64(3)}},
+		},
+		{
+			source: "SELECT id FROM items WHERE name LIKE '%!%' ESCAPE '!'",
+			rows:   [][]backend.TValue{{int64(3)}},
+		},
+		{
+			source: `SELECT 'a_c' LIKE 'a\_c', 'abc' LIKE 'a\_c', 'abc' LIKE 'a%%c', '' LIKE '%'`,
+			rows:   [][]backend.TValue{{true, false, true, true}},
+		},
+		{
+			source: `SELECT id, CASE WHEN price < 5 THEN 'cheap' WHEN price < 10 THEN 'fair' ELSE 'dear' END
+				FROM items ORDER BY id`,
+			rows: [][]backend.TValue{{int64(1), "cheap"}, {int64(2), "fair"}, {int64(3), "fair"}, {int64(4), "dear"}},
+		},
+		{
+			source: "SELECT CASE id WHEN 1 THEN 'one' WHEN 2 THEN 'two' END FROM items ORDER BY id",
+			rows:   [][]backend.TValue{{"one"}, {"two"}, {nil}, {nil}},
+		},
+		{
+			source: "SELECT CASE NULL WHEN NULL THEN 1 ELSE 2 END, CASE WHEN NULL THEN 1 END",
+			rows:   [][]backend.TValue{{int64(2), nil}},
+		},
+		{
+			source: `SELECT CASE WHEN price > 4 THEN 'big' ELSE 'small' END AS size, count(*)
+				FROM items GROUP BY CASE WHEN price > 4 THEN 'big' ELSE 'small' END ORDER BY size`,
+			rows: [][]backend.TValue{{"big", int64(3)}, {"small", int64(1)}},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	results, err := execute(t, mb, "SELECT CASE WHEN id = 1 THEN 1 ELSE 2.5 END AS x FROM items")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "x", results.Columns[0].Name)
+		assert.Equal(t, backend.NumericType, results.Columns[0].Type)
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "SELECT 1 IN ('a', 2)", err: backend.ErrTypeMismatch},
+		{source: "SELECT price LIKE 'a' FROM items", err: backend.ErrTypeMismatch},
+		{source: "SELECT 'a' BETWEEN 1 AND 2", err: backend.ErrTypeMismatch},
+		{source: "SELECT CASE WHEN true THEN 1 ELSE 'a' END", err: backend.ErrTypeMismatch},
+		{source: "SELECT CASE WHEN 1 THEN 1 END", err: backend.ErrTypeMismatch},
+		{source: "SELECT 'a' LIKE 'a' ESCAPE 'xy'", err: backend.ErrInvalidValue},
+		{source: `SELECT 'a' LIKE 'a\'`, err: backend.ErrInvalidValue},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), "%s: %v", test.source, err)
+	}
+}
+
+func TestBackend_Functions(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE people (id INT, name TEXT, nick TEXT, born TIMESTAMP, score DOUBLE PRECISION);
+		INSERT INTO people VALUES
+			(1, '  Ann ', NULL, '2024-05-17 13:45:30', -2.5),
+			(2, 'Bob', 'bobby', '2023-12-31 23:59:59', 7.25);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT lower('AbC'), upper('AbC'), length('héllo'), trim('  x  '), trim('xxaxx', 'x')",
+			rows:   [][]backend.TValue{{"abc", "ABC", int64(5), "x", "a"}},
+		},
+		{
+			source: "SELECT substr('database', 5), substr('database', 0, 3), substr('database', 20), replace('a-b-c', '-', '+')",
+			rows:   [][]backend.TValue{{"base", "da", "", "a+b+c"}},
+		},
+		{
+			source: "SELECT concat('a', NULL, 1, true), concat(NULL), lower(NULL)",
+			rows:   [][]backend.TValue{{"a1true", "", nil}},
+		},
+		{
+			source: "SELECT abs(-3), abs(-2.5), round(2.5), round(-2.5), round(7), round(3.14159, 2), round(1234, -2)",
+			rows: [][]backend.TValue{{
+				int64(3),
+				backend.TNumeric("2.5"),
+				backend.TNumeric("3"),
+				backend.TNumeric("-3"),
+				int64(7),
+				backend.TNumeric("3.14"),
+				backend.TNumeric("1200"),
+			}},
+		},
+		{
+			source: "SELECT floor(-1.5), ceil(-1.5), floor(4), mod(7, 3), mod(-7, 3), power(2, 10), power(4, 0.5)",
+			rows:   [][]backend.TValue{{backend.TNumeric("-2"), backend.TNumeric("-1"), int64(4), int64(1), int64(-1), 1024.0, 2.0}},
+		},
+		{
+			source: "SELECT id, coalesce(nick, trim(name), 'none'), nullif(id, 1) FROM people ORDER BY id",
+			rows:   [][]backend.TValue{{int64(1), "Ann", nil}, {int64(2), "bobby", int64(2)}},
+		},
+		{
+			source: "SELECT extract(year FROM born), extract('month', born), EXTRACT(dow FROM born) FROM people ORDER BY id",
+			rows: [][]backend.TValue{
+				{backend.TNumeric("2024"), backend.TNumeric("5"), backend.TNumeric("5")},
+				{backend.TNumeric("2023"), backend.TNumeric("12"), backend.TNumeric("0")},
+			},
+		},
+		{
+			source: "SELECT id FROM people WHERE abs(score) > 3 AND upper(name) LIKE 'B%'",
+			rows:   [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: "SELECT round(avg(abs(score)), 1), max(length(name)) FROM people",
+			rows:   [][]backend.TValue{{backend.TNumeric("4.9"), int64(6)}},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	results, err := execute(t, mb, `
+		SELECT date_trunc('month', born), date_trunc('week', born), date_trunc('hour', born), now()
+		FROM people WHERE id = 1
+	`)
+	if assert.Nil(t, err) && assert.Len(t, results.Rows, 1) {
+		row := results.Rows[0]
+		assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), row[0])
+		assert.Equal(t, time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), row[1])
+		assert.Equal(t, time.Date(2024, 5, 17, 13, 0, 0, 0, time.UTC), row[2])
+		assert.WithinDuration(t, time.Now(), row[3].(time.Time), time.Minute)
+		assert.Equal(t, backend.TimestampType, results.Columns[0].Type)
+		assert.Equal(t, "date_trunc", results.Columns[0].Name)
+	}
+
+	_, err = execute(t, mb, `
+		CREATE TABLE stamps (id INT, at TIMESTAMP, added TIMESTAMP DEFAULT now());
+		INSERT INTO stamps (id, at) VALUES (1, now()), (2, now());
+	`)
+	assert.Nil(t, err)
+
+	results, err = execute(t, mb, `
+		SELECT count(DISTINCT at), count(DISTINCT added), min(at) = max(added), now() = now()
+		FROM stamps
+	`)
+	if assert.Nil(t, err) {
+		assert.Equal(t, [][]backend.TValue{{int64(1), int64(1), true, true}}, results.Rows)
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "SELECT lower(1)", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT lower('a', 'b')", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT substr('a')", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT nosuch(1)", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT now(1)", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT coalesce(1, 'a')", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT upper(DISTINCT name) FROM people", err: backend.ErrInvalidGrouping},
+		{source: "SELECT substr('abc', 1, -1)", err: backend.ErrInvalidValue},
+		{source: "SELECT mod(1, 0)", err: backend.ErrDivisionByZero},
+		{source: "SELECT power(0, -1)", err: backend.ErrInvalidValue},
+		{source: "SELECT abs(-9223372036854775807 - 1)", err: backend.ErrOutOfRange},
+		{source: "SELECT date_trunc('fortnight', born) FROM people", err: backend.ErrInvalidValue},
+		{source: "SELECT extract(year FROM 'soon')", err: backend.ErrInvalidValue},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), "%s: %v", test.source, err)
+	}
+}
+
+type tProductAccumulator struct {
+	product float64
+	nulls   int64
+}
+
+func (accumulator *tProductAccumulator) Add(value backend.TValue) error {
+	if value == nil {
+		accumulator.nulls++
+		return nil
+	}
+
+	accumulator.product *= value.(float64)
+
+	return nil
+}
+
+func (accumulator *tProductAccumulator) Result() (backend.TValue, error) {
+	if accumulator.nulls > 0 {
+		return nil, nil
+	}
+
+	return accumulator.product, nil
+}
+
+func TestBackend_UserFunctions(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+	calls := int64(0)
+
+	assert.Nil(t, mb.RegisterFunction("Initials", &backend.TFunction{
+		Arguments:     []backend.EColumnType{backend.TextType},
+		Variadic:      true,
+		Result:        backend.TextType,
+		Deterministic: true,
+		Evaluate: func(arguments []backend.TValue) (backend.TValue, error) {
+			initials := ""
+			for _, argument := range arguments {
+				initials += argument.(string)[:1]
+			}
+
+			return initials, nil
+		},
+	}))
+	assert.Nil(t, mb.RegisterFunction("half", &backend.TFunction{
+		Arguments:     []backend.EColumnType{backend.DoubleType},
+		Result:        backend.IntType,
+		Deterministic: true,
+		Evaluate: func(arguments []backend.TValue) (backend.TValue, error) {
+			return arguments[0].(float64) / 2, nil
+		},
+	}))
+	assert.Nil(t, mb.RegisterFunction("is_missing", &backend.TFunction{
+		Arguments:     []backend.EColumnType{backend.UnknownType},
+		Result:        backend.BoolType,
+		Deterministic: true,
+		CallOnNull:    true,
+		Evaluate: func(arguments []backend.TValue) (backend.TValue, error) {
+			return arguments[0] == nil, nil
+		},
+	}))
+	assert.Nil(t, mb.RegisterFunction("next_call", &backend.TFunction{
+		Result: backend.BigIntType,
+		Evaluate: func([]backend.TValue) (backend.TValue, error) {
+			calls++
+			return calls, nil
+		},
+	}))
+	assert.Nil(t, mb.RegisterAggregate("product", &backend.TAggregate{
+		Argument:   backend.DoubleType,
+		Result:     backend.DoubleType,
+		CallOnNull: true,
+		New:        func() backend.TAccumulator { return &tProductAccumulator{product: 1} },
+	}))
+
+	registrationErrors := []error{
+		mb.RegisterFunction("initials", &backend.TFunction{Evaluate: func([]backend.TValue) (backend.TValue, error) { return nil, nil }}),
+		mb.RegisterFunction("lower", &backend.TFunction{Evaluate: func([]backend.TValue) (backend.TValue, error) { return nil, nil }}),
+		mb.RegisterAggregate("count", &backend.TAggregate{New: func() backend.TAccumulator { return &tProductAccumulator{} }}),
+		mb.RegisterFunction("product", &backend.TFunction{Evaluate: func([]backend.TValue) (backend.TValue, error) { return nil, nil }}),
+	}
+	for _, err := range registrationErrors {
+		assert.True(t, errors.Is(err, backend.ErrFunctionAlreadyExists), "%v", err)
+	}
+
+	identity := func(arguments []backend.TValue) (backend.TValue, error) { return arguments[0], nil }
+	invalidRegistrations := []error{
+		mb.RegisterFunction("unimplemented", &backend.TFunction{}),
+		mb.RegisterFunction("anyval", &backend.TFunction{
+			Arguments: []backend.EColumnType{backend.UnknownType},
+			Result:    backend.UnknownType,
+			Evaluate:  identity,
+		}),
+		mb.RegisterAggregate("anyagg", &backend.TAggregate{
+			Argument: backend.UnknownType,
+			Result:   backend.UnknownType,
+			New:      func() backend.TAccumulator { return &tProductAccumulator{} },
+		}),
+		mb.RegisterFunction("double", &backend.TFunction{Result: backend.IntType, Evaluate: identity}),
+		mb.RegisterFunction("select", &backend.TFunction{Result: backend.IntType, Evaluate: identity}),
+		mb.RegisterFunction("two words", &backend.TFunction{Result: backend.IntType, Evaluate: identity}),
+		mb.RegisterFunction(`"quoted"`, &backend.TFunction{Result: backend.IntType, Evaluate: identity}),
+	}
+	for _, err := range invalidRegistrations {
+		assert.True(t, errors.Is(err, backend.ErrInvalidStatement), "%v", err)
+	}
+
+	assert.Nil(t, mb.RegisterFunction("key", &backend.TFunction{
+		Arguments:     []backend.EColumnType{backend.IntType},
+		Result:        backend.IntType,
+		Deterministic: true,
+		Evaluate:      identity,
+	}))
+
+	_, err := execute(t, mb, `
+		CREATE TABLE people (id INT, given TEXT, family TEXT, grp INT, factor INT);
+		INSERT INTO people VALUES
+			(1, 'Ada', 'Lovelace', 1, 2),
+			(2, 'Alan', 'Turing', 1, 3),
+			(3, 'Grace', 'Hopper', 2, NULL);
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT initials(given, family), INITIALS(given), half(factor), is_missing(factor) FROM people ORDER BY id",
+			rows: [][]backend.TValue{
+				{"AL", "A", int64(1), false},
+				{"AT", "A", int64(2), false},
+				{"GH", "G", nil, true},
+			},
+		},
+		{
+			source: "SELECT grp, product(factor) FROM people GROUP BY grp ORDER BY grp",
+			rows:   [][]backend.TValue{{int64(1), 6.0}, {int64(2), nil}},
+		},
+		{
+			source: "SELECT half(product(factor)) FROM people WHERE factor IS NOT NULL",
+			rows:   [][]backend.TValue{{int64(3)}},
+		},
+		{
+			source: "SELECT id FROM people WHERE initials(given, family) IN ('AT', 'GH') ORDER BY id",
+			rows:   [][]backend.TValue{{int64(2)}, {int64(3)}},
+		},
+		{
+			source: "SELECT key(id) FROM people ORDER BY id LIMIT key(1)",
+			rows:   [][]backend.TValue{{int64(1)}},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	results, err := execute(t, mb, "SELECT next_call() FROM people")
+	if assert.Nil(t, err) {
+		assert.Equal(t, [][]backend.TValue{{int64(1)}, {int64(2)}, {int64(3)}}, results.Rows)
+		assert.Equal(t, backend.BigIntType, results.Columns[0].Type)
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "SELECT initials()", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT initials(given, 1) FROM people", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT half('two')", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT anyval(1)", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT lower(anyval(1))", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT 1 LIMIT anyval('x')", err: backend.ErrFunctionDoesNotExist},
+		{source: "SELECT product(given) FROM people", err: backend.ErrFunctionDoesNotExist},
+		{source: "CREATE TABLE t (a INT CHECK (a > next_call()))", err: backend.ErrInvalidConstraint},
+		{source: "CREATE TABLE t (a INT CHECK (a > now()))", err: backend.ErrInvalidConstraint},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), "%s: %v", test.source, err)
+	}
+
+	other := backend.NewMemoryBackend()
+
+	_, err = execute(t, other, "SELECT initials('x')")
+	assert.True(t, errors.Is(err, backend.ErrFunctionDoesNotExist), "%v", err)
+
+	assert.Nil(t, other.RegisterFunction("initials", &backend.TFunction{
+		Result: backend.IntType,
+		Evaluate: func([]backend.TValue) (backend.TValue, error) {
+			return int64(0), nil
+		},
+	}))
+}
+
+func TestBackend_Casts(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE items (id INT, code TEXT, price DOUBLE PRECISION, active BOOLEAN, added DATE);
+		INSERT INTO items VALUES
+			(1, '42', 9.75, true, '2024-02-29'),
+			(2, 'x7', 120.5, false, '2023-01-01');
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT CAST('12' AS INT), '2.5'::double precision, 3::text, CAST(7 AS numeric(4, 1)) / 2",
+			rows:   [][]backend.TValue{{int64(12), 2.5, "3", backend.TNumeric("3.5")}},
+		},
+		{
+			source: "SELECT true::int, 0::boolean, 'yes'::boolean, CAST(NULL AS DATE), 'abcdef'::varchar(3)",
+			rows:   [][]backend.TValue{{int64(1), false, true, nil, "abc"}},
+		},
+		{
+			source: "SELECT price::int, added::timestamp, added::text FROM items WHERE id = 1",
+			rows: [][]backend.TValue{
+				{int64(10), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), "2024-02-29"},
+			},
+		},
+		{
+			source: "SELECT id FROM items WHERE code = 42::text OR id::text = '2' ORDER BY id",
+			rows:   [][]backend.TValue{{int64(1)}, {int64(2)}},
+		},
+		{
+			source: "SELECT -price::int, CAST(active AS int) + 1 FROM items ORDER BY id",
+			rows:   [][]backend.TValue{{int64(-10), int64(2)}, {int64(-121), int64(1)}},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	results, err := execute(t, mb, "SELECT code::int, CAST(price AS INT), '1'::bigint FROM items WHERE id = 1")
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"code", "price", "bigint"}, []string{
+			results.Columns[0].Name,
+			results.Columns[1].Name,
+			results.Columns[2].Name,
+		})
+		assert.Equal(t, []backend.EColumnType{backend.IntType, backend.IntType, backend.BigIntType}, []backend.EColumnType{
+			results.Columns[0].Type,
+			results.Columns[1].Type,
+			results.Columns[2].Type,
+		})
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+		msg    string
+	}{
+		{source: "SELECT id FROM items WHERE id = code", err: backend.ErrTypeMismatch, msg: "cannot compare INT with TEXT"},
+		{source: "SELECT added::int FROM items", err: backend.ErrTypeMismatch, msg: "cannot cast DATE to INT"},
+		{source: "SELECT price::boolean FROM items", err: backend.ErrTypeMismatch, msg: "cannot cast DOUBLE PRECISION to BOOLEAN"},
+		{source: "SELECT code::int FROM items", err: backend.ErrInvalidValue},
+		{source: "SELECT 1000::numeric(3)", err: backend.ErrOutOfRange},
+		{source: "SELECT 70000::smallint", err: backend.ErrOutOfRange},
+		{source: "SELECT 1::varchar(0)", err: backend.ErrInvalidDatatype},
+		{source: "UPDATE items SET id = active WHERE false", err: backend.ErrTypeMismatch, msg: "column items.id is INT but expression is BOOLEAN"},
+		{source: "INSERT INTO items (id, added) VALUES (3, 5)", err: backend.ErrTypeMismatch},
+		{source: "INSERT INTO items (active) SELECT price FROM items", err: backend.ErrTypeMismatch},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		if assert.True(t, errors.Is(err, test.err), "%s: %v", test.source, err) && test.msg != "" {
+			assert.Contains(t, err.Error(), test.msg, test.source)
+		}
+	}
+
+	_, err = execute(t, mb, "UPDATE items SET id = active::int + 10, code = price WHERE id = 2")
+	assert.Nil(t, err)
+
+	results, err = execute(t, mb, "SELECT id, code FROM items ORDER BY id")
+	if assert.Nil(t, err) {
+		assert.Equal(t, [][]backend.TValue{{int64(1), "42"}, {int64(10), "120.5"}}, results.Rows)
+	}
+}
+
+func TestBackend_SetOperations(t *testing.T) {
+	mb := backend.NewMemoryBackend()
+
+	_, err := execute(t, mb, `
+		CREATE TABLE online (customer TEXT, amount INT);
+		CREATE TABLE store (customer TEXT, amount DOUBLE PRECISION, opened DATE);
+		INSERT INTO online VALUES ('ann', 10), ('bob', 20), ('bob', 20), (NULL, 5);
+		INSERT INTO store VALUES ('bob', 20, '2024-01-01'), ('cat', 7.5, '2024-01-02'), (NULL, 5, '2024-01-03');
+	`)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		source string
+		rows   [][]backend.TValue
+	}{
+		{
+			source: "SELECT customer FROM online UNION SELECT customer FROM store ORDER BY customer",
+			rows:   [][]backend.TValue{{"ann"}, {"bob"}, {"cat"}, {nil}},
+		},
+		{
+			source: "SELECT customer, amount FROM online UNION ALL SELECT customer, amount FROM store ORDER BY 2 DESC, 1 LIMIT 3 OFFSET 1",
+			rows:   [][]backend.TValue{{"bob", 20.0}, {"bob", 20.0}, {"ann", 10.0}},
+		},
+		{
+			source: "SELECT customer, amount FROM online INTERSECT SELECT customer, amount FROM store ORDER BY customer",
+			rows:   [][]backend.TValue{{"bob", 20.0}, {nil, 5.0}},
+		},
+		{
+			source: "SELECT customer FROM online INTERSECT ALL SELECT customer FROM online WHERE amount > 10",
+			rows:   [][]backend.TValue{{"bob"}, {"bob"}},
+		},
+		{
+			source: "SELECT customer FROM online EXCEPT SELECT customer FROM store",
+			rows:   [][]backend.TValue{{"ann"}},
+		},
+		{
+			source: "SELECT customer FROM online EXCEPT ALL SELECT customer FROM store ORDER BY customer",
+			rows:   [][]backend.TValue{{"ann"}, {"bob"}},
+		},
+		{
+			source: "SELECT 1 UNION SELECT 2 INTERSECT SELECT 3",
+			rows:   [][]backend.TValue{{int64(1)}},
+		},
+		{
+			source: "SELECT 1 EXCEPT SELECT 1 UNION SELECT 2",
+			rows:   [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: "SELECT n FROM (SELECT 1 AS n UNION ALL SELECT 2) AS t WHERE n IN (SELECT 2 UNION SELECT 3)",
+			rows:   [][]backend.TValue{{int64(2)}},
+		},
+		{
+			source: "SELECT opened FROM store WHERE amount < 6 UNION SELECT '2024-02-01'::timestamp",
+			rows: [][]backend.TValue{
+				{time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+				{time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		results, err := execute(t, mb, test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.rows, results.Rows, test.source)
+		}
+	}
+
+	results, err := execute(t, mb, "SELECT customer AS who, amount FROM online UNION SELECT 'zed', 1.5")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "who", results.Columns[0].Name)
+		assert.Equal(t, backend.TextType, results.Columns[0].Type)
+		assert.Equal(t, backend.NumericType, results.Columns[1].Type)
+		assert.Len(t, results.Rows, 4)
+	}
+
+	_, err = execute(t, mb, "INSERT INTO online SELECT customer, amount::int FROM store EXCEPT SELECT customer, amount FROM online")
+	assert.Nil(t, err)
+
+	results, err = execute(t, mb, "SELECT count(*) FROM online WHERE customer = 'cat'")
+	if assert.Nil(t, err) {
+		assert.Equal(t, [][]backend.TValue{{int64(1)}}, results.Rows)
+	}
+
+	errorTests := []struct {
+		source string
+		err    error
+	}{
+		{source: "SELECT customer FROM online UNION SELECT customer, amount FROM store", err: backend.ErrInvalidStatement},
+		{source: "SELECT amount FROM online EXCEPT SELECT customer FROM store", err: backend.ErrTypeMismatch},
+		{source: "SELECT customer FROM online UNION SELECT customer FROM store ORDER BY amount", err: backend.ErrColumnDoesNotExist},
+		{source: "SELECT amount FROM online UNION SELECT amount FROM store ORDER BY sum(amount)", err: backend.ErrInvalidGrouping},
+		{source: "SELECT 1 UNION SELECT 2 LIMIT 'a'", err: backend.ErrTypeMismatch},
+	}
+
+	for _, test := range errorTests {
+		_, err := execute(t, mb, test.source)
+		assert.True(t, errors.Is(err, test.err), "%s: %v", test.source, err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"pkg/backend"
+	"pkg/parser"
+)
+
+const joinBenchmarkRows = 1000
+
+// joinBackend creates tables l and r whose id columns both hold 0..rows-1,
+// in ascending order when sorted is set and shuffled otherwise.
+func joinBackend(b *testing.B, rows int, sorted bool) *backend.TMemoryBackend {
+	mb := backend.NewMemoryBackend()
+	random := rand.New(rand.NewSource(1))
+
+	for _, table := range []string{"l", "r"} {
+		ids := random.Perm(rows)
+		if sorted {
+			for i := range ids {
+				ids[i] = i
+			}
+		}
+
+		values := make([]string, rows)
+		for i, id := range ids {
+			values[i] = fmt.Sprintf("(%d, %d)", id, i)
+		}
+
+		_, err := execute(b, mb, fmt.Sprintf(
+			"CREATE TABLE %s (id INT, v INT); INSERT INTO %s VALUES %s",
+			table,
+			table,
+			strings.Join(values, ", "),
+		))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return mb
+}
+
+func benchmarkJoin(b *testing.B, sorted bool, source string) {
+	mb := joinBackend(b, joinBenchmarkRows, sorted)
+
+	tree, err := parser.Parse(source)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		results, err := mb.Execute(tree.Statements[0])
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if count := results.Rows[0][0]; count != int64(joinBenchmarkRows) {
+			b.Fatalf("Expected %d joined rows, got %v", joinBenchmarkRows, count)
+		}
+	}
+}
+
+// The range predicate is equivalent to l.id = r.id but is not an equi-join,
+// so it runs as a nested loop.
+func BenchmarkJoin_NestedLoop(b *testing.B) {
+	benchmarkJoin(b, false, "SELECT COUNT(*) FROM l JOIN r ON l.id <= r.id AND l.id >= r.id")
+}
+
+func BenchmarkJoin_Hash(b *testing.B) {
+	benchmarkJoin(b, false, "SELECT COUNT(*) FROM l JOIN r ON l.id = r.id")
+}
+
+func BenchmarkJoin_Merge(b *testing.B) {
+	benchmarkJoin(b, true, "SELECT COUNT(*) FROM l JOIN r ON l.id = r.id")
+}
+
+func BenchmarkJoin_HashUsing(b *testing.B) {
+	benchmarkJoin(b, false, "SELECT COUNT(*) FROM l JOIN r USING (id)")
+}
+
+func BenchmarkJoin_LeftHash(b *testing.B) {
+	benchmarkJoin(b, false, "SELECT COUNT(*) FROM l LEFT JOIN r ON l.id = r.id AND r.v >= 0")
+}
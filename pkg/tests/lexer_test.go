@@ -42,6 +42,50 @@ func TestLexer_CheckReservedKeyword(testing *testing.T) {
 			keyword: false,
 			value:   "flubbrety",
 		},
+		{
+			keyword: true,
+			value:   "and",
+		},
+		{
+			keyword: true,
+			value:   "order",
+		},
+		{
+			keyword: false,
+			value:   "orders",
+		},
+		{
+			keyword: false,
+			value:   "notes",
+		},
+		{
+			keyword: true,
+			value:   "NULL",
+		},
+		{
+			keyword: true,
+			value:   "true",
+		},
+		{
+			keyword: false,
+			value:   "nullable",
+		},
+		{
+			keyword: false,
+			value:   "first",
+		},
+		{
+			keyword: false,
+			value:   "last",
+		},
+		{
+			keyword: false,
+			value:   "key",
+		},
+		{
+			keyword: false,
+			value:   "date",
+		},
 	}
 
 	for _, test := range tests {
@@ -172,9 +216,29 @@ func TestLexer_CheckSymbol(t *testing.T) {
 			value:  "",
 		},
 		{
-			symbol: false,
+			symbol: true,
 			value:  "=",
 		},
+		{
+			symbol: true,
+			value:  "<=",
+		},
+		{
+			symbol: true,
+			value:  "<>",
+		},
+		{
+			symbol: true,
+			value:  "||",
+		},
+		{
+			symbol: true,
+			value:  "::",
+		},
+		{
+			symbol: false,
+			value:  "!",
+		},
 	}
 
 	for _, test := range tests {
@@ -305,3 +369,100 @@ func TestToken_CheckString(t *testing.T) {
 		}
 	}
 }
+
+func TestLexer_CheckComment(t *testing.T) {
+	tests := []struct {
+		comment bool
+		input   string
+		value   string
+	}{
+		{
+			comment: true,
+			input:   "-- note\nSELECT",
+			value:   "-- note",
+		},
+		{
+			comment: true,
+			input:   "--",
+			value:   "--",
+		},
+		{
+			comment: true,
+			input:   "/* a /* nested */ b */ 1",
+			value:   "/* a /* nested */ b */",
+		},
+		{
+			comment: false,
+			input:   "/* open /* nested */",
+		},
+		{
+			comment: false,
+			input:   "- 1",
+		},
+		{
+			comment: false,
+			input:   "/ 2",
+		},
+	}
+
+	for _, test := range tests {
+		tok, _, ok := lexer.CheckComment(test.input, lexer.TCursor{})
+		assert.Equal(t, test.comment, ok, test.input)
+		if ok {
+			assert.Equal(t, test.value, tok.Value, test.input)
+		}
+	}
+}
+
+func TestLexer_TokenizeComments(t *testing.T) {
+	source := "-- header\nSELECT /* first\n   second */ a -- trailing\nFROM t;"
+
+	tokens, err := lexer.Tokenize(source)
+	assert.Nil(t, err)
+	assert.Equal(t, []*lexer.TToken{
+		{Value: "select", Type: lexer.ReservedType, Loc: lexer.TTokenLocation{Line: 1, Column: 0}},
+		{Value: "a", Type: lexer.IdentifierType, Loc: lexer.TTokenLocation{Line: 2, Column: 13}},
+		{Value: "from", Type: lexer.ReservedType, Loc: lexer.TTokenLocation{Line: 3, Column: 0}},
+		{Value: "t", Type: lexer.IdentifierType, Loc: lexer.TTokenLocation{Line: 3, Column: 5}},
+		{Value: ";", Type: lexer.SymbolType, Loc: lexer.TTokenLocation{Line: 3, Column: 6}},
+	}, tokens)
+
+	tokens, err = lexer.TokenizeWithOptions(source, lexer.TTokenizeOptions{KeepComments: true})
+	assert.Nil(t, err)
+
+	comments := []*lexer.TToken{}
+	for _, token := range tokens {
+		if token.Type == lexer.CommentType {
+			comments = append(comments, token)
+		}
+	}
+
+	assert.Equal(t, []*lexer.TToken{
+		{Value: "-- header", Type: lexer.CommentType, Loc: lexer.TTokenLocation{Line: 0, Column: 0}},
+		{Value: "/* first\n   second */", Type: lexer.CommentType, Loc: lexer.TTokenLocation{Line: 1, Column: 7}},
+		{Value: "-- trailing", Type: lexer.CommentType, Loc: lexer.TTokenLocation{Line: 2, Column: 15}},
+	}, comments)
+
+	_, err = lexer.Tokenize("SELECT 1 /* unterminated")
+	assert.NotNil(t, err)
+}
+
+func TestLexer_TokenizeErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		message string
+	}{
+		{source: "!", message: "Unable to lex token, at 0:0"},
+		{source: "SELECT !", message: `Unable to lex token after "select", at 0:7`},
+		{source: "SELECT 'a", message: "Unterminated string, at 0:7"},
+		{source: "SELECT \"a", message: "Unterminated quoted identifier, at 0:7"},
+		{source: "SELECT /* a", message: "Unterminated block comment, at 0:7"},
+	}
+
+	for _, test := range tests {
+		_, err := lexer.Tokenize(test.source)
+		if assert.NotNil(t, err, test.source) {
+			assert.Equal(t, test.message, err.Error(), test.source)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"pkg/ast"
 	"pkg/lexer"
 	"pkg/parser"
@@ -21,28 +22,37 @@ func TestParse(t *testing.T) {
 					{
 						Type: ast.SelectType,
 						Select: &ast.TSelectStatement{
-							Rules: []*ast.TExpression{
+							Rules: []*ast.TSelectRule{
 								{
-									Type: ast.LiteralType,
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
-										Type:  lexer.IdentifierType,
-										Value: "id",
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "id",
+										},
 									},
 								},
 								{
-									Type: ast.LiteralType,
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 11, Line: 0},
-										Type:  lexer.IdentifierType,
-										Value: "name",
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 11, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "name",
+										},
 									},
 								},
 							},
-							From: lexer.TToken{
-								Loc:   lexer.TTokenLocation{Column: 21, Line: 0},
-								Type:  lexer.IdentifierType,
-								Value: "sketchy name",
+							From: &ast.TTableExpression{
+								Table: &ast.TTableReference{
+									Table: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 21, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "sketchy name",
+									},
+								},
+								Type: ast.TableReferenceType,
 							},
 						},
 					},
@@ -56,28 +66,37 @@ func TestParse(t *testing.T) {
 					{
 						Type: ast.SelectType,
 						Select: &ast.TSelectStatement{
-							Rules: []*ast.TExpression{
+							Rules: []*ast.TSelectRule{
 								{
-									Type: ast.LiteralType,
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
-										Type:  lexer.IdentifierType,
-										Value: "id",
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "id",
+										},
 									},
 								},
 								{
-									Type: ast.LiteralType,
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 11, Line: 0},
-										Type:  lexer.IdentifierType,
-										Value: "name",
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 11, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "name",
+										},
 									},
 								},
 							},
-							From: lexer.TToken{
-								Loc:   lexer.TTokenLocation{Column: 21, Line: 0},
-								Type:  lexer.IdentifierType,
-								Value: "sketchy name",
+							From: &ast.TTableExpression{
+								Table: &ast.TTableReference{
+									Table: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 21, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "sketchy name",
+									},
+								},
+								Type: ast.TableReferenceType,
 							},
 						},
 					},
@@ -91,29 +110,35 @@ func TestParse(t *testing.T) {
 					{
 						Type: ast.SelectType,
 						Select: &ast.TSelectStatement{
-							Rules: []*ast.TExpression{
+							Rules: []*ast.TSelectRule{
 								{
-									Type: ast.LiteralType,
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
-										Type:  lexer.NumericType,
-										Value: "1",
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "1",
+										},
 									},
 								},
 								{
-									Type: ast.LiteralType,
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 11, Line: 0},
-										Type:  lexer.NumericType,
-										Value: "2",
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 10, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "2",
+										},
 									},
 								},
 								{
-									Type: ast.LiteralType,
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 15, Line: 0},
-										Type:  lexer.NumericType,
-										Value: "3",
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 13, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "3",
+										},
 									},
 								},
 							},
@@ -134,20 +159,22 @@ func TestParse(t *testing.T) {
 								Type:  lexer.IdentifierType,
 								Value: "users",
 							},
-							Values: &[]*ast.TExpression{
-								{
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 26, Line: 0},
-										Type:  lexer.NumericType,
-										Value: "105",
-									}, Type: ast.LiteralType,
-								},
+							Values: [][]*ast.TExpression{
 								{
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 32, Line: 0},
-										Type:  lexer.IdentifierType,
-										Value: "string",
-									}, Type: ast.LiteralType,
+									{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 26, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "105",
+										}, Type: ast.LiteralType,
+									},
+									{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 31, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "string",
+										}, Type: ast.LiteralType,
+									},
 								},
 							},
 						},
@@ -167,20 +194,22 @@ func TestParse(t *testing.T) {
 								Type:  lexer.IdentifierType,
 								Value: "users",
 							},
-							Values: &[]*ast.TExpression{
-								{
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 26, Line: 0},
-										Type:  lexer.NumericType,
-										Value: "105",
-									}, Type: ast.LiteralType,
-								},
+							Values: [][]*ast.TExpression{
 								{
-									Literal: &lexer.TToken{
-										Loc:   lexer.TTokenLocation{Column: 32, Line: 0},
-										Type:  lexer.StringType,
-										Value: "string",
-									}, Type: ast.LiteralType,
+									{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 26, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "105",
+										}, Type: ast.LiteralType,
+									},
+									{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 31, Line: 0},
+											Type:  lexer.StringType,
+											Value: "string",
+										}, Type: ast.LiteralType,
+									},
 								},
 							},
 						},
@@ -231,11 +260,1547 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
-	}
-
-	for _, test := range tests {
-		ast, err := parser.Parse(test.source)
-		assert.Nil(t, err, test.source)
-		assert.Equal(t, test.ast, ast, test.source)
+		{
+			source: "SELECT 1 - 2 - 3 * 4",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.BinaryType,
+										Binary: &ast.TBinaryExpression{
+											A: &ast.TExpression{
+												Type: ast.BinaryType,
+												Binary: &ast.TBinaryExpression{
+													A: &ast.TExpression{
+														Type: ast.LiteralType,
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+															Type:  lexer.NumericType,
+															Value: "1",
+														},
+													},
+													B: &ast.TExpression{
+														Type: ast.LiteralType,
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 11, Line: 0},
+															Type:  lexer.NumericType,
+															Value: "2",
+														},
+													},
+													Operator: lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 9, Line: 0},
+														Type:  lexer.SymbolType,
+														Value: "-",
+													},
+												},
+											},
+											B: &ast.TExpression{
+												Type: ast.BinaryType,
+												Binary: &ast.TBinaryExpression{
+													A: &ast.TExpression{
+														Type: ast.LiteralType,
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 15, Line: 0},
+															Type:  lexer.NumericType,
+															Value: "3",
+														},
+													},
+													B: &ast.TExpression{
+														Type: ast.LiteralType,
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 19, Line: 0},
+															Type:  lexer.NumericType,
+															Value: "4",
+														},
+													},
+													Operator: lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 17, Line: 0},
+														Type:  lexer.SymbolType,
+														Value: "*",
+													},
+												},
+											},
+											Operator: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 13, Line: 0},
+												Type:  lexer.SymbolType,
+												Value: "-",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT NOT (a)",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.UnaryType,
+										Unary: &ast.TUnaryExpression{
+											Operand: &ast.TExpression{
+												Type: ast.LiteralType,
+												Literal: &lexer.TToken{
+													Loc:   lexer.TTokenLocation{Column: 12, Line: 0},
+													Type:  lexer.IdentifierType,
+													Value: "a",
+												},
+											},
+											Operator: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+												Type:  lexer.ReservedType,
+												Value: "not",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT id FROM users WHERE id",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "id",
+										},
+									},
+								},
+							},
+							From: &ast.TTableExpression{
+								Table: &ast.TTableReference{
+									Table: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 15, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "users",
+									},
+								},
+								Type: ast.TableReferenceType,
+							},
+							Where: &ast.TExpression{
+								Type: ast.LiteralType,
+								Literal: &lexer.TToken{
+									Loc:   lexer.TTokenLocation{Column: 27, Line: 0},
+									Type:  lexer.IdentifierType,
+									Value: "id",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "UPDATE users SET name = 'x'",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.UpdateType,
+						Update: &ast.TUpdateStatement{
+							Table: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "users",
+							},
+							Assignments: []*ast.TAssignment{
+								{
+									Column: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 17, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "name",
+									},
+									Value: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 24, Line: 0},
+											Type:  lexer.StringType,
+											Value: "x",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "DELETE FROM users WHERE id",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.DeleteType,
+						Delete: &ast.TDeleteStatement{
+							Table: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 12, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "users",
+							},
+							Where: &ast.TExpression{
+								Type: ast.LiteralType,
+								Literal: &lexer.TToken{
+									Loc:   lexer.TTokenLocation{Column: 24, Line: 0},
+									Type:  lexer.IdentifierType,
+									Value: "id",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "DROP TABLE IF EXISTS users",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.DropTableType,
+						DropTable: &ast.TDropTableStatement{
+							TableName: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 21, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "users",
+							},
+							IfExists: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "ALTER TABLE users ADD COLUMN age INT DEFAULT 18",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.AlterTableType,
+						AlterTable: &ast.TAlterTableStatement{
+							TableName: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 12, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "users",
+							},
+							Action: ast.AddColumnAction,
+							Column: &ast.TColumnMeta{
+								Name: lexer.TToken{
+									Loc:   lexer.TTokenLocation{Column: 29, Line: 0},
+									Type:  lexer.IdentifierType,
+									Value: "age",
+								},
+								Datatype: lexer.TToken{
+									Loc:   lexer.TTokenLocation{Column: 33, Line: 0},
+									Type:  lexer.ReservedType,
+									Value: "int",
+								},
+								Default: &ast.TExpression{
+									Type: ast.LiteralType,
+									Literal: &lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 45, Line: 0},
+										Type:  lexer.NumericType,
+										Value: "18",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "ALTER TABLE users RENAME COLUMN age TO years",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.AlterTableType,
+						AlterTable: &ast.TAlterTableStatement{
+							TableName: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 12, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "users",
+							},
+							Action: ast.RenameColumnAction,
+							Target: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 32, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "age",
+							},
+							NewName: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 39, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "years",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "CREATE TABLE t (a INT PRIMARY KEY, b TEXT NOT NULL UNIQUE, CHECK (a))",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.CreateTableType,
+						CreateTable: &ast.TCreateTableStatement{
+							TableName: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 13, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "t",
+							},
+							Columns: &[]*ast.TColumnMeta{
+								{
+									Name: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 16, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "a",
+									},
+									Datatype: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 18, Line: 0},
+										Type:  lexer.ReservedType,
+										Value: "int",
+									},
+									PrimaryKey: true,
+								},
+								{
+									Name: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 35, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "b",
+									},
+									Datatype: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 37, Line: 0},
+										Type:  lexer.ReservedType,
+										Value: "text",
+									},
+									NotNull: true,
+									Unique:  true,
+								},
+							},
+							Constraints: []*ast.TTableConstraint{
+								{
+									Type: ast.CheckConstraint,
+									Check: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 66, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "a",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "CREATE TABLE key (first TEXT, date DATE, PRIMARY KEY (first))",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.CreateTableType,
+						CreateTable: &ast.TCreateTableStatement{
+							TableName: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 13, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "key",
+							},
+							Columns: &[]*ast.TColumnMeta{
+								{
+									Name: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 18, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "first",
+									},
+									Datatype: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 24, Line: 0},
+										Type:  lexer.ReservedType,
+										Value: "text",
+									},
+								},
+								{
+									Name: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 30, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "date",
+									},
+									Datatype: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 35, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "date",
+									},
+								},
+							},
+							Constraints: []*ast.TTableConstraint{
+								{
+									Type: ast.PrimaryKeyConstraint,
+									Columns: []lexer.TToken{
+										{
+											Loc:   lexer.TTokenLocation{Column: 54, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "first",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "CREATE TABLE t (p NUMERIC(10, 2), d DOUBLE PRECISION)",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.CreateTableType,
+						CreateTable: &ast.TCreateTableStatement{
+							TableName: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 13, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "t",
+							},
+							Columns: &[]*ast.TColumnMeta{
+								{
+									Name: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 16, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "p",
+									},
+									Datatype: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 18, Line: 0},
+										Type:  lexer.ReservedType,
+										Value: "numeric",
+									},
+									DatatypeParams: []lexer.TToken{
+										{
+											Loc:   lexer.TTokenLocation{Column: 26, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "10",
+										},
+										{
+											Loc:   lexer.TTokenLocation{Column: 30, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "2",
+										},
+									},
+								},
+								{
+									Name: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 34, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "d",
+									},
+									Datatype: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 36, Line: 0},
+										Type:  lexer.ReservedType,
+										Value: "double",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT NOT a IS NOT DISTINCT FROM TRUE",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.UnaryType,
+										Unary: &ast.TUnaryExpression{
+											Operand: &ast.TExpression{
+												Type: ast.IsType,
+												Is: &ast.TIsExpression{
+													A: &ast.TExpression{
+														Type: ast.LiteralType,
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 11, Line: 0},
+															Type:  lexer.IdentifierType,
+															Value: "a",
+														},
+													},
+													B: &ast.TExpression{
+														Type: ast.LiteralType,
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 34, Line: 0},
+															Type:  lexer.BooleanType,
+															Value: "true",
+														},
+													},
+													Not:      true,
+													Distinct: true,
+												},
+											},
+											Operator: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+												Type:  lexer.ReservedType,
+												Value: "not",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "INSERT INTO t (b) VALUES (1), (2)",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.InsertType,
+						Insert: &ast.TInsertStatement{
+							Table: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 12, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "t",
+							},
+							Columns: []lexer.TToken{
+								{
+									Loc:   lexer.TTokenLocation{Column: 15, Line: 0},
+									Type:  lexer.IdentifierType,
+									Value: "b",
+								},
+							},
+							Values: [][]*ast.TExpression{
+								{
+									{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 26, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "1",
+										}, Type: ast.LiteralType,
+									},
+								},
+								{
+									{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 31, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "2",
+										}, Type: ast.LiteralType,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "CREATE TABLE t AS SELECT a FROM s",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.CreateTableType,
+						CreateTable: &ast.TCreateTableStatement{
+							TableName: lexer.TToken{
+								Loc:   lexer.TTokenLocation{Column: 13, Line: 0},
+								Type:  lexer.IdentifierType,
+								Value: "t",
+							},
+							As: &ast.TSelectStatement{
+								Rules: []*ast.TSelectRule{
+									{
+										Expression: &ast.TExpression{
+											Type: ast.LiteralType,
+											Literal: &lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 25, Line: 0},
+												Type:  lexer.IdentifierType,
+												Value: "a",
+											},
+										},
+									},
+								},
+								From: &ast.TTableExpression{
+									Table: &ast.TTableReference{
+										Table: lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 32, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "s",
+										},
+									},
+									Type: ast.TableReferenceType,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT u.id AS key, name n FROM users u",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.QualifiedType,
+										Qualified: &ast.TQualifiedColumn{
+											Table: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+												Type:  lexer.IdentifierType,
+												Value: "u",
+											},
+											Column: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 9, Line: 0},
+												Type:  lexer.IdentifierType,
+												Value: "id",
+											},
+										},
+									},
+									Alias: &lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 15, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "key",
+									},
+								},
+								{
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 20, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "name",
+										},
+									},
+									Alias: &lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 25, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "n",
+									},
+								},
+							},
+							From: &ast.TTableExpression{
+								Table: &ast.TTableReference{
+									Table: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 32, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "users",
+									},
+									Alias: &lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 38, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "u",
+									},
+								},
+								Type: ast.TableReferenceType,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT a FROM t ORDER BY a DESC NULLS LAST, 1 OFFSET 1 LIMIT 2",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "a",
+										},
+									},
+								},
+							},
+							From: &ast.TTableExpression{
+								Table: &ast.TTableReference{
+									Table: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 14, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "t",
+									},
+								},
+								Type: ast.TableReferenceType,
+							},
+							OrderBy: []*ast.TOrderTerm{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 25, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "a",
+										},
+									},
+									Descending: true,
+									Nulls:      ast.NullsLast,
+								},
+								{
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 44, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "1",
+										},
+									},
+								},
+							},
+							Limit: &ast.TExpression{
+								Type: ast.LiteralType,
+								Literal: &lexer.TToken{
+									Loc:   lexer.TTokenLocation{Column: 61, Line: 0},
+									Type:  lexer.NumericType,
+									Value: "2",
+								},
+							},
+							Offset: &ast.TExpression{
+								Type: ast.LiteralType,
+								Literal: &lexer.TToken{
+									Loc:   lexer.TTokenLocation{Column: 53, Line: 0},
+									Type:  lexer.NumericType,
+									Value: "1",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT COUNT(*), SUM(DISTINCT a) FROM t GROUP BY b HAVING MAX(a) > 1",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.FunctionType,
+										Function: &ast.TFunctionCall{
+											Name: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+												Type:  lexer.IdentifierType,
+												Value: "count",
+											},
+											Star: true,
+										},
+									},
+								},
+								{
+									Expression: &ast.TExpression{
+										Type: ast.FunctionType,
+										Function: &ast.TFunctionCall{
+											Name: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 17, Line: 0},
+												Type:  lexer.IdentifierType,
+												Value: "sum",
+											},
+											Arguments: []*ast.TExpression{
+												{
+													Type: ast.LiteralType,
+													Literal: &lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 30, Line: 0},
+														Type:  lexer.IdentifierType,
+														Value: "a",
+													},
+												},
+											},
+											Distinct: true,
+										},
+									},
+								},
+							},
+							From: &ast.TTableExpression{
+								Table: &ast.TTableReference{
+									Table: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 38, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "t",
+									},
+								},
+								Type: ast.TableReferenceType,
+							},
+							GroupBy: []*ast.TExpression{
+								{
+									Type: ast.LiteralType,
+									Literal: &lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 49, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "b",
+									},
+								},
+							},
+							Having: &ast.TExpression{
+								Type: ast.BinaryType,
+								Binary: &ast.TBinaryExpression{
+									A: &ast.TExpression{
+										Type: ast.FunctionType,
+										Function: &ast.TFunctionCall{
+											Name: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 58, Line: 0},
+												Type:  lexer.IdentifierType,
+												Value: "max",
+											},
+											Arguments: []*ast.TExpression{
+												{
+													Type: ast.LiteralType,
+													Literal: &lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 62, Line: 0},
+														Type:  lexer.IdentifierType,
+														Value: "a",
+													},
+												},
+											},
+										},
+									},
+									B: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 67, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "1",
+										},
+									},
+									Operator: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 65, Line: 0},
+										Type:  lexer.SymbolType,
+										Value: ">",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT a FROM t LEFT OUTER JOIN u ON t.id = u.id, v",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Type: ast.LiteralType,
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "a",
+										},
+									},
+								},
+							},
+							From: &ast.TTableExpression{
+								Join: &ast.TJoin{
+									Left: &ast.TTableExpression{
+										Join: &ast.TJoin{
+											Left: &ast.TTableExpression{
+												Table: &ast.TTableReference{
+													Table: lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 14, Line: 0},
+														Type:  lexer.IdentifierType,
+														Value: "t",
+													},
+												},
+												Type: ast.TableReferenceType,
+											},
+											Right: &ast.TTableExpression{
+												Table: &ast.TTableReference{
+													Table: lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 32, Line: 0},
+														Type:  lexer.IdentifierType,
+														Value: "u",
+													},
+												},
+												Type: ast.TableReferenceType,
+											},
+											Type: ast.LeftJoin,
+											On: &ast.TExpression{
+												Type: ast.BinaryType,
+												Binary: &ast.TBinaryExpression{
+													A: &ast.TExpression{
+														Type: ast.QualifiedType,
+														Qualified: &ast.TQualifiedColumn{
+															Table: lexer.TToken{
+																Loc:   lexer.TTokenLocation{Column: 37, Line: 0},
+																Type:  lexer.IdentifierType,
+																Value: "t",
+															},
+															Column: lexer.TToken{
+																Loc:   lexer.TTokenLocation{Column: 39, Line: 0},
+																Type:  lexer.IdentifierType,
+																Value: "id",
+															},
+														},
+													},
+													B: &ast.TExpression{
+														Type: ast.QualifiedType,
+														Qualified: &ast.TQualifiedColumn{
+															Table: lexer.TToken{
+																Loc:   lexer.TTokenLocation{Column: 44, Line: 0},
+																Type:  lexer.IdentifierType,
+																Value: "u",
+															},
+															Column: lexer.TToken{
+																Loc:   lexer.TTokenLocation{Column: 46, Line: 0},
+																Type:  lexer.IdentifierType,
+																Value: "id",
+															},
+														},
+													},
+													Operator: lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 42, Line: 0},
+														Type:  lexer.SymbolType,
+														Value: "=",
+													},
+												},
+											},
+										},
+										Type: ast.JoinExpressionType,
+									},
+									Right: &ast.TTableExpression{
+										Table: &ast.TTableReference{
+											Table: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 50, Line: 0},
+												Type:  lexer.IdentifierType,
+												Value: "v",
+											},
+										},
+										Type: ast.TableReferenceType,
+									},
+									Type: ast.CrossJoin,
+								},
+								Type: ast.JoinExpressionType,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT *, u.* FROM users u",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{Star: true},
+								{
+									Star: true,
+									Table: &lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 10, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "u",
+									},
+								},
+							},
+							From: &ast.TTableExpression{
+								Table: &ast.TTableReference{
+									Table: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 19, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "users",
+									},
+									Alias: &lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 25, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "u",
+									},
+								},
+								Type: ast.TableReferenceType,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT a FROM (SELECT b FROM t) x WHERE a NOT IN (SELECT c FROM u)",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "a",
+										},
+										Type: ast.LiteralType,
+									},
+								},
+							},
+							From: &ast.TTableExpression{
+								Derived: &ast.TDerivedTable{
+									Select: &ast.TSelectStatement{
+										Rules: []*ast.TSelectRule{
+											{
+												Expression: &ast.TExpression{
+													Literal: &lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 22, Line: 0},
+														Type:  lexer.IdentifierType,
+														Value: "b",
+													},
+													Type: ast.LiteralType,
+												},
+											},
+										},
+										From: &ast.TTableExpression{
+											Table: &ast.TTableReference{
+												Table: lexer.TToken{
+													Loc:   lexer.TTokenLocation{Column: 29, Line: 0},
+													Type:  lexer.IdentifierType,
+													Value: "t",
+												},
+											},
+											Type: ast.TableReferenceType,
+										},
+									},
+									Alias: &lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 32, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "x",
+									},
+								},
+								Type: ast.DerivedTableType,
+							},
+							Where: &ast.TExpression{
+								In: &ast.TInExpression{
+									A: &ast.TExpression{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 40, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "a",
+										},
+										Type: ast.LiteralType,
+									},
+									Subquery: &ast.TSelectStatement{
+										Rules: []*ast.TSelectRule{
+											{
+												Expression: &ast.TExpression{
+													Literal: &lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 57, Line: 0},
+														Type:  lexer.IdentifierType,
+														Value: "c",
+													},
+													Type: ast.LiteralType,
+												},
+											},
+										},
+										From: &ast.TTableExpression{
+											Table: &ast.TTableReference{
+												Table: lexer.TToken{
+													Loc:   lexer.TTokenLocation{Column: 64, Line: 0},
+													Type:  lexer.IdentifierType,
+													Value: "u",
+												},
+											},
+											Type: ast.TableReferenceType,
+										},
+									},
+									Not: true,
+								},
+								Type: ast.InType,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT CASE a WHEN 1 THEN 'x' END FROM t WHERE a NOT BETWEEN 1 AND 2",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Case: &ast.TCaseExpression{
+											Operand: &ast.TExpression{
+												Literal: &lexer.TToken{
+													Loc:   lexer.TTokenLocation{Column: 12, Line: 0},
+													Type:  lexer.IdentifierType,
+													Value: "a",
+												},
+												Type: ast.LiteralType,
+											},
+											Whens: []*ast.TWhenClause{
+												{
+													Condition: &ast.TExpression{
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 19, Line: 0},
+															Type:  lexer.NumericType,
+															Value: "1",
+														},
+														Type: ast.LiteralType,
+													},
+													Result: &ast.TExpression{
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 26, Line: 0},
+															Type:  lexer.StringType,
+															Value: "x",
+														},
+														Type: ast.LiteralType,
+													},
+												},
+											},
+										},
+										Type: ast.CaseType,
+									},
+								},
+							},
+							From: &ast.TTableExpression{
+								Table: &ast.TTableReference{
+									Table: lexer.TToken{
+										Loc:   lexer.TTokenLocation{Column: 39, Line: 0},
+										Type:  lexer.IdentifierType,
+										Value: "t",
+									},
+								},
+								Type: ast.TableReferenceType,
+							},
+							Where: &ast.TExpression{
+								Between: &ast.TBetweenExpression{
+									A: &ast.TExpression{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 47, Line: 0},
+											Type:  lexer.IdentifierType,
+											Value: "a",
+										},
+										Type: ast.LiteralType,
+									},
+									Low: &ast.TExpression{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 61, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "1",
+										},
+										Type: ast.LiteralType,
+									},
+									High: &ast.TExpression{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 67, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "2",
+										},
+										Type: ast.LiteralType,
+									},
+									Not: true,
+								},
+								Type: ast.BetweenType,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT extract(year FROM d)",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Function: &ast.TFunctionCall{
+											Name: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+												Type:  lexer.IdentifierType,
+												Value: "extract",
+											},
+											Arguments: []*ast.TExpression{
+												{
+													Literal: &lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 15, Line: 0},
+														Type:  lexer.StringType,
+														Value: "year",
+													},
+													Type: ast.LiteralType,
+												},
+												{
+													Literal: &lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 25, Line: 0},
+														Type:  lexer.IdentifierType,
+														Value: "d",
+													},
+													Type: ast.LiteralType,
+												},
+											},
+										},
+										Type: ast.FunctionType,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT CAST(a AS varchar(3)), -b::int",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Rules: []*ast.TSelectRule{
+								{
+									Expression: &ast.TExpression{
+										Cast: &ast.TCastExpression{
+											Operand: &ast.TExpression{
+												Literal: &lexer.TToken{
+													Loc:   lexer.TTokenLocation{Column: 12, Line: 0},
+													Type:  lexer.IdentifierType,
+													Value: "a",
+												},
+												Type: ast.LiteralType,
+											},
+											Datatype: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 17, Line: 0},
+												Type:  lexer.ReservedType,
+												Value: "varchar",
+											},
+											DatatypeParams: []lexer.TToken{
+												{
+													Loc:   lexer.TTokenLocation{Column: 25, Line: 0},
+													Type:  lexer.NumericType,
+													Value: "3",
+												},
+											},
+										},
+										Type: ast.CastType,
+									},
+								},
+								{
+									Expression: &ast.TExpression{
+										Unary: &ast.TUnaryExpression{
+											Operand: &ast.TExpression{
+												Cast: &ast.TCastExpression{
+													Operand: &ast.TExpression{
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 31, Line: 0},
+															Type:  lexer.IdentifierType,
+															Value: "b",
+														},
+														Type: ast.LiteralType,
+													},
+													Datatype: lexer.TToken{
+														Loc:   lexer.TTokenLocation{Column: 34, Line: 0},
+														Type:  lexer.ReservedType,
+														Value: "int",
+													},
+												},
+												Type: ast.CastType,
+											},
+											Operator: lexer.TToken{
+												Loc:   lexer.TTokenLocation{Column: 30, Line: 0},
+												Type:  lexer.SymbolType,
+												Value: "-",
+											},
+										},
+										Type: ast.UnaryType,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			source: "SELECT 1 UNION SELECT 2 INTERSECT ALL SELECT 3 ORDER BY 1",
+			ast: &ast.TSyntaxTree{
+				Statements: []*ast.TStatement{
+					{
+						Type: ast.SelectType,
+						Select: &ast.TSelectStatement{
+							Compound: &ast.TCompoundSelect{
+								Operation: ast.UnionOperation,
+								Left: &ast.TSelectStatement{
+									Rules: []*ast.TSelectRule{
+										{
+											Expression: &ast.TExpression{
+												Literal: &lexer.TToken{
+													Loc:   lexer.TTokenLocation{Column: 7, Line: 0},
+													Type:  lexer.NumericType,
+													Value: "1",
+												},
+												Type: ast.LiteralType,
+											},
+										},
+									},
+								},
+								Right: &ast.TSelectStatement{
+									Compound: &ast.TCompoundSelect{
+										Operation: ast.IntersectOperation,
+										All:       true,
+										Left: &ast.TSelectStatement{
+											Rules: []*ast.TSelectRule{
+												{
+													Expression: &ast.TExpression{
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 22, Line: 0},
+															Type:  lexer.NumericType,
+															Value: "2",
+														},
+														Type: ast.LiteralType,
+													},
+												},
+											},
+										},
+										Right: &ast.TSelectStatement{
+											Rules: []*ast.TSelectRule{
+												{
+													Expression: &ast.TExpression{
+														Literal: &lexer.TToken{
+															Loc:   lexer.TTokenLocation{Column: 45, Line: 0},
+															Type:  lexer.NumericType,
+															Value: "3",
+														},
+														Type: ast.LiteralType,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							OrderBy: []*ast.TOrderTerm{
+								{
+									Expression: &ast.TExpression{
+										Literal: &lexer.TToken{
+											Loc:   lexer.TTokenLocation{Column: 56, Line: 0},
+											Type:  lexer.NumericType,
+											Value: "1",
+										},
+										Type: ast.LiteralType,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		ast, err := parser.Parse(test.source)
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.ast, ast, test.source)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		source   string
+		line     uint
+		column   uint
+		offset   uint
+		token    string
+		expected []string
+		message  string
+		excerpt  string
+	}{
+		{
+			source:   "SELECT a + 1 2 FROM t",
+			column:   13,
+			offset:   13,
+			token:    "2",
+			expected: []string{"AS", `","`, "FROM", "WHERE", "GROUP", "HAVING", "ORDER", "LIMIT", "OFFSET", "UNION", "INTERSECT", "EXCEPT", `";"`},
+			excerpt:  "SELECT a + 1 2 FROM t\n             ^",
+		},
+		{
+			source:   "CREATE TABLE t (\n\tid INT,\n\tname FOO\n)",
+			line:     2,
+			column:   6,
+			offset:   32,
+			token:    "foo",
+			expected: []string{"datatype"},
+			excerpt:  "\tname FOO\n\t     ^",
+		},
+		{
+			source:   "INSERT INTO t VALUES (1, 2",
+			column:   26,
+			offset:   26,
+			expected: []string{`","`, `")"`},
+			excerpt:  "INSERT INTO t VALUES (1, 2\n                          ^",
+		},
+		{
+			source:   "SELECT 1; DELETE t",
+			column:   17,
+			offset:   17,
+			token:    "t",
+			expected: []string{"FROM"},
+			excerpt:  "SELECT 1; DELETE t\n                 ^",
+		},
+		{
+			source:   "SELECT a FROM t ORDER BY a NULLS",
+			column:   32,
+			offset:   32,
+			expected: []string{"FIRST", "LAST"},
+			excerpt:  "SELECT a FROM t ORDER BY a NULLS\n                                ^",
+		},
+		{
+			source:   "SELECT a FROM t JOIN u",
+			column:   22,
+			offset:   22,
+			expected: []string{"ON", "USING"},
+			excerpt:  "SELECT a FROM t JOIN u\n                      ^",
+		},
+		{
+			source:   "SELECT;",
+			column:   6,
+			offset:   6,
+			token:    ";",
+			expected: []string{"expression"},
+			excerpt:  "SELECT;\n      ^",
+		},
+		{
+			source:   "SELECT a FROM t WHERE EXISTS a",
+			column:   29,
+			offset:   29,
+			token:    "a",
+			expected: []string{`"("`},
+			excerpt:  "SELECT a FROM t WHERE EXISTS a\n                             ^",
+		},
+		{
+			source:   "SELECT CASE a END",
+			column:   14,
+			offset:   14,
+			token:    "end",
+			expected: []string{"WHEN"},
+			excerpt:  "SELECT CASE a END\n              ^",
+		},
+		{
+			source:   "SELECT a::bogus",
+			column:   10,
+			offset:   10,
+			token:    "bogus",
+			expected: []string{"datatype"},
+			excerpt:  "SELECT a::bogus\n          ^",
+		},
+		{
+			source:   "SELECT CAST(a int)",
+			column:   14,
+			offset:   14,
+			token:    "int",
+			expected: []string{"AS"},
+			excerpt:  "SELECT CAST(a int)\n              ^",
+		},
+		{
+			source:   "SELECT a FROM t UNION ALL FROM u",
+			column:   26,
+			offset:   26,
+			token:    "from",
+			expected: []string{"SELECT"},
+			excerpt:  "SELECT a FROM t UNION ALL FROM u\n                          ^",
+		},
+		{
+			source:  "SELECT 1 /* open",
+			column:  9,
+			offset:  9,
+			message: "Unterminated block comment",
+			excerpt: "SELECT 1 /* open\n         ^",
+		},
+		{
+			source:  "CREATE TABLE t (id INT NULL NOT NULL)",
+			column:  28,
+			offset:  28,
+			token:   "not",
+			message: `Conflicting NULL and NOT NULL in definition of column "id"`,
+			excerpt: "CREATE TABLE t (id INT NULL NOT NULL)\n                            ^",
+		},
+		{
+			source:  "CREATE TABLE t (id INT PRIMARY KEY NULL)",
+			column:  35,
+			offset:  35,
+			token:   "null",
+			message: `Conflicting NULL and NOT NULL in definition of column "id"`,
+			excerpt: "CREATE TABLE t (id INT PRIMARY KEY NULL)\n                                   ^",
+		},
+		{
+			source:  "CREATE TABLE t (id INT DEFAULT 1 DEFAULT 2)",
+			column:  33,
+			offset:  33,
+			token:   "default",
+			message: `Duplicate DEFAULT in definition of column "id"`,
+			excerpt: "CREATE TABLE t (id INT DEFAULT 1 DEFAULT 2)\n                                 ^",
+		},
+		{
+			source:  "CREATE TABLE t (id INT UNIQUE NOT NULL UNIQUE)",
+			column:  39,
+			offset:  39,
+			token:   "unique",
+			message: `Duplicate UNIQUE in definition of column "id"`,
+			excerpt: "CREATE TABLE t (id INT UNIQUE NOT NULL UNIQUE)\n                                       ^",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := parser.Parse(test.source)
+
+		var parseErr *parser.ParseError
+		if !assert.True(t, errors.As(err, &parseErr), test.source) {
+			continue
+		}
+
+		assert.Equal(t, test.line, parseErr.Line, test.source)
+		assert.Equal(t, test.column, parseErr.Column, test.source)
+		assert.Equal(t, test.offset, parseErr.Offset, test.source)
+		assert.Equal(t, test.expected, parseErr.Expected, test.source)
+		assert.Equal(t, test.message, parseErr.Message, test.source)
+		assert.Equal(t, test.excerpt, parseErr.Excerpt, test.source)
+
+		if test.token == "" {
+			assert.Nil(t, parseErr.Token, test.source)
+		} else if assert.NotNil(t, parseErr.Token, test.source) {
+			assert.Equal(t, test.token, parseErr.Token.Value, test.source)
+		}
 	}
 }